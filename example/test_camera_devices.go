@@ -49,22 +49,22 @@ func main() {
 
 	// 示例1：使用默认摄像头
 	fmt.Println("\n🎬 示例1：使用默认摄像头 ('camera')")
-	liveWindow1 := gui.NewYOLOLiveWindow(detector, "camera", options)
+	liveWindow1 := gui.NewYOLOLiveWindow(detector, gui.InputTypeCamera, "camera", options)
 	liveWindow1.Run()
 
 	// 示例2：使用第一个摄像头
 	fmt.Println("\n🎬 示例2：使用第一个摄像头 ('0')")
-	liveWindow2 := gui.NewYOLOLiveWindow(detector, "0", options)
+	liveWindow2 := gui.NewYOLOLiveWindow(detector, gui.InputTypeCamera, "0", options)
 	liveWindow2.Run()
 
 	// 示例3：使用第二个摄像头
 	fmt.Println("\n🎬 示例3：使用第二个摄像头 ('1')")
-	liveWindow3 := gui.NewYOLOLiveWindow(detector, "1", options)
+	liveWindow3 := gui.NewYOLOLiveWindow(detector, gui.InputTypeCamera, "1", options)
 	liveWindow3.Run()
 
 	// 示例4：使用Windows设备路径
 	fmt.Println("\n🎬 示例4：使用Windows设备路径 ('video=0')")
-	liveWindow4 := gui.NewYOLOLiveWindow(detector, "video=0", options)
+	liveWindow4 := gui.NewYOLOLiveWindow(detector, gui.InputTypeCamera, "video=0", options)
 	liveWindow4.Run()
 
 	fmt.Println("✅ 摄像头设备检测示例完成！")
@@ -42,7 +42,7 @@ func main() {
 	fmt.Println("   - '/dev/video0', '/dev/video1' (Linux)")
 
 	// 启动摄像头检测
-	liveWindow := gui.NewYOLOLiveWindow(detector, "camera", options)
+	liveWindow := gui.NewYOLOLiveWindow(detector, gui.InputTypeCamera, "camera", options)
 	liveWindow.Run()
 
 	fmt.Println("✅ 摄像头检测完成！")
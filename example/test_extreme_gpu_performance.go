@@ -20,7 +20,7 @@ func main() {
 		InputSize:   640,
 		LibraryPath: "D:\\onnxruntime-win-x64-1.22.1\\lib\\onnxruntime.dll",
 	}
-	detector, err := yolo.NewYOLO(config)
+	detector, err := yolo.NewYOLOFromModelConfig(config)
 	if err != nil {
 		panic(fmt.Sprintf("创建检测器失败: %v", err))
 	}
@@ -64,7 +64,7 @@ func main() {
 
 			// 显示极致性能统计
 			fmt.Printf("\r🔥 帧 %d | 当前FPS: %.1f | 最高FPS: %.1f | 检测数: %d | GPU利用率: 疯狂模式 | 批处理: %d | 工作线程: %d",
-				result.FrameIndex, currentFPS, maxFPS, len(result.Detections),
+				result.FrameNumber, currentFPS, maxFPS, len(result.Detections),
 				processor.GetOptimization().GetMaxBatchSize(),
 				processor.GetOptimization().GetParallelWorkers())
 
@@ -15,7 +15,7 @@ func main() {
 	fmt.Println("========================================")
 
 	// 1. 创建YOLO检测器（启用GPU优化）
-	detector, err := yolo.NewYOLO("../yolov8n.onnx", yolo.WithGPU(true))
+	detector, err := yolo.NewYOLO("../yolov8n.onnx", "../coco.yaml", yolo.DefaultConfig().WithGPU(true))
 	if err != nil {
 		log.Fatal("创建YOLO检测器失败:", err)
 	}
@@ -36,6 +36,7 @@ func main() {
 	optimization := processor.GetOptimization()
 
 	fmt.Println("📊 GPU 状态验证:")
+	fmt.Printf("   执行提供者: %s\n", detector.ActiveProvider())
 	fmt.Printf("   GPU启用: %v\n", optimization.IsGPUEnabled())
 	fmt.Printf("   CUDA启用: %v\n", optimization.IsCUDAEnabled())
 	fmt.Printf("   CUDA设备ID: %d\n", optimization.GetCUDADeviceID())
@@ -46,7 +46,7 @@ func main() {
 
 	// 4. 初始化YOLO检测器
 	fmt.Println("🔧 初始化YOLO检测器...")
-	detector, err := yolo.NewYOLO(modelPath, config)
+	detector, err := yolo.NewYOLO(modelPath, "../coco.yaml", config)
 	if err != nil {
 		log.Fatalf("初始化YOLO失败: %v", err)
 	}
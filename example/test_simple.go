@@ -32,7 +32,7 @@ func main() {
 
 	// 直接启动GUI窗口进行实时检测
 	fmt.Println("🎬 启动实时检测窗口...")
-	liveWindow := gui.NewYOLOLiveWindow(detector, "test.mp4", options)
+	liveWindow := gui.NewYOLOLiveWindow(detector, gui.InputTypeFile, "test.mp4", options)
 	liveWindow.Run()
 
 	fmt.Println("✅ 实时检测完成！")
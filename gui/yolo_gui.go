@@ -5,6 +5,8 @@ import (
 	"image"
 	"image/color"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -19,9 +21,13 @@ import (
 
 	"github.com/Cubiaa/yolo-go/yolo"
 
+	vidio "github.com/AlexEidt/Vidio"
 	"github.com/disintegration/imaging"
 )
 
+// recordQueueSize 录制帧队列长度，编码跟不上时丢弃新帧而不阻塞UI
+const recordQueueSize = 30
+
 // 输入源类型常量
 const (
 	InputTypeFile    = "file"    // 文件输入
@@ -42,12 +48,26 @@ type YOLOLiveWindow struct {
 	imageDisplay *canvas.Image
 	statusLabel  *widget.Label
 	fpsLabel     *widget.Label
+	confSlider   *widget.Slider
+	iouSlider    *widget.Slider
+
+	// 当前帧检测结果列表
+	detectionList     *widget.List
+	currentDetections []yolo.Detection
 
 	// 输入源信息
 	inputSource *yolo.InputSource
 	videoPath   string
-	isPlaying   bool
+	isPlaying   atomic.Bool // UI协程和处理协程共享，需原子读写
+	isPaused    atomic.Bool
 	stopChan    chan bool
+	stepChan    chan struct{}
+	pauseBtn    *widget.Button
+
+	// 录制状态（编码在独立协程中进行，不阻塞UI）
+	recordMu     sync.Mutex
+	recordFrames chan image.Image // 待编码的帧，停止录制时关闭
+	recordBtn    *widget.Button
 
 	// 检测配置
 	drawBoxes     bool
@@ -68,8 +88,8 @@ type YOLOLiveWindow struct {
 
 	// 性能统计
 	frameCount int
-	startTime  time.Time
 	fps        float64
+	fpsMeter   *yolo.FPSMeter // 滑动窗口帧率（忽略预热帧）
 }
 
 // NewYOLOLiveWindow 创建实时视频播放窗口
@@ -128,7 +148,9 @@ func NewYOLOLiveWindow(detector *yolo.YOLO, inputType string, inputPath string,
 		lineWidth:     lineWidth,
 		fontSize:      fontSize,
 		showFPS:       options.ShowFPS,
-		stopChan:      make(chan bool),
+		fpsMeter:      yolo.NewFPSMeter(options.FPSWindow, options.FPSWarmupFrames),
+		stopChan:      make(chan bool, 1), // 带缓冲，停止时无需等待接收方
+		stepChan:      make(chan struct{}, 1),
 
 		// 性能配置 - 针对高性能CPU优化
 		performanceMode: "fast",
@@ -149,6 +171,11 @@ func NewYOLOLiveWindow(detector *yolo.YOLO, inputType string, inputPath string,
 	return window
 }
 
+// NewYOLOLiveWindowWithType 按明确的输入源类型创建实时视频播放窗口（与NewYOLOLiveWindow行为一致）
+func NewYOLOLiveWindowWithType(detector *yolo.YOLO, inputType string, inputPath string, options *yolo.DetectionOptions) *YOLOLiveWindow {
+	return NewYOLOLiveWindow(detector, inputType, inputPath, options)
+}
+
 // detectInputType 自动检测输入源类型
 func detectInputType(inputPath string) string {
 	// 网络流检测
@@ -265,17 +292,74 @@ func (live *YOLOLiveWindow) createWindow() {
 	})
 	performanceSelect.SetSelected(live.performanceMode)
 
+	// 创建阈值滑块，拖动时实时更新检测器配置
+	confLabel := widget.NewLabel(fmt.Sprintf("置信度: %.2f", live.confThreshold))
+	live.confSlider = widget.NewSlider(0.01, 1.0)
+	live.confSlider.Step = 0.01
+	live.confSlider.SetValue(live.confThreshold)
+	live.confSlider.OnChanged = func(value float64) {
+		live.confThreshold = value
+		confLabel.SetText(fmt.Sprintf("置信度: %.2f", value))
+		live.detector.SetRuntimeConfig(live.detectionOptions())
+	}
+
+	iouLabel := widget.NewLabel(fmt.Sprintf("IOU: %.2f", live.iouThreshold))
+	live.iouSlider = widget.NewSlider(0.01, 1.0)
+	live.iouSlider.Step = 0.01
+	live.iouSlider.SetValue(live.iouThreshold)
+	live.iouSlider.OnChanged = func(value float64) {
+		live.iouThreshold = value
+		iouLabel.SetText(fmt.Sprintf("IOU: %.2f", value))
+		live.detector.SetRuntimeConfig(live.detectionOptions())
+	}
+
 	// 创建控制按钮
 	playBtn := widget.NewButton("播放", live.startPlayback)
 	stopBtn := widget.NewButton("停止", live.stopPlayback)
+	live.pauseBtn = widget.NewButton("暂停", live.togglePause)
+	stepBtn := widget.NewButton("单帧", live.stepFrame)
+	snapshotBtn := widget.NewButton("保存截图", func() {
+		path, err := live.SaveSnapshot()
+		if err != nil {
+			live.statusLabel.SetText(fmt.Sprintf("保存截图失败: %v", err))
+			return
+		}
+		live.statusLabel.SetText(fmt.Sprintf("截图已保存: %s", path))
+	})
+
+	live.recordBtn = widget.NewButton("录制", live.toggleRecording)
+
+	// 创建检测结果列表（显示当前帧的类别、置信度和检测框）
+	live.detectionList = widget.NewList(
+		func() int {
+			return len(live.currentDetections)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("person 0.00 [0000,0000,0000,0000]")
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			if id >= len(live.currentDetections) {
+				return
+			}
+			det := live.currentDetections[id]
+			item.(*widget.Label).SetText(fmt.Sprintf("%s %.2f [%.0f,%.0f,%.0f,%.0f]",
+				det.Class, det.Score, det.Box[0], det.Box[1], det.Box[2], det.Box[3]))
+		},
+	)
+	listPanel := container.NewBorder(widget.NewLabel("检测结果"), nil, nil, nil, live.detectionList)
 
 	// 创建设备信息标签
 	deviceInfo := widget.NewLabel(fmt.Sprintf("设备: %s", live.inputSource.Path))
 
 	// 创建布局
-	controls := container.NewHBox(playBtn, stopBtn, widget.NewLabel("性能模式:"), performanceSelect, live.statusLabel, live.fpsLabel)
+	controls := container.NewHBox(playBtn, live.pauseBtn, stepBtn, stopBtn, snapshotBtn, live.recordBtn, widget.NewLabel("性能模式:"), performanceSelect, live.statusLabel, live.fpsLabel)
+	thresholds := container.NewGridWithColumns(2,
+		container.NewBorder(nil, nil, confLabel, nil, live.confSlider),
+		container.NewBorder(nil, nil, iouLabel, nil, live.iouSlider),
+	)
 	infoPanel := container.NewHBox(deviceInfo)
-	content := container.NewVBox(live.imageDisplay, controls, infoPanel)
+	bottom := container.NewVBox(controls, thresholds, infoPanel)
+	content := container.NewBorder(nil, bottom, nil, listPanel, live.imageDisplay)
 
 	live.window.SetContent(content)
 
@@ -287,13 +371,20 @@ func (live *YOLOLiveWindow) createWindow() {
 
 // startPlayback 开始播放
 func (live *YOLOLiveWindow) startPlayback() {
-	if live.isPlaying {
+	if !live.isPlaying.CompareAndSwap(false, true) {
 		return
 	}
 
-	live.isPlaying = true
-	live.startTime = time.Now()
+	// 清除上一次遗留的停止信号
+	select {
+	case <-live.stopChan:
+	default:
+	}
+
+	live.isPaused.Store(false)
 	live.frameCount = 0
+	live.fps = 0
+	live.fpsMeter.Reset()
 
 	fyne.Do(func() {
 		live.statusLabel.SetText("正在播放...")
@@ -303,12 +394,201 @@ func (live *YOLOLiveWindow) startPlayback() {
 	go live.processVideo()
 }
 
+// togglePause 暂停或继续播放（暂停时保持视频流不关闭）
+func (live *YOLOLiveWindow) togglePause() {
+	if !live.isPlaying.Load() {
+		return
+	}
+
+	paused := !live.isPaused.Load()
+	live.isPaused.Store(paused)
+	if paused {
+		live.pauseBtn.SetText("继续")
+		live.statusLabel.SetText("已暂停")
+	} else {
+		live.pauseBtn.SetText("暂停")
+		live.statusLabel.SetText("正在播放...")
+	}
+}
+
+// stepFrame 暂停状态下前进一帧（播放中调用时先暂停）
+func (live *YOLOLiveWindow) stepFrame() {
+	if !live.isPlaying.Load() {
+		return
+	}
+
+	if !live.isPaused.Load() {
+		live.togglePause()
+		return
+	}
+
+	select {
+	case live.stepChan <- struct{}{}:
+	default:
+		// 上一次单帧请求尚未处理
+	}
+}
+
+// waitIfPaused 暂停时阻塞帧循环，直到继续播放、单帧前进或停止
+// 返回false表示播放已停止
+func (live *YOLOLiveWindow) waitIfPaused() bool {
+	for live.isPaused.Load() && live.isPlaying.Load() {
+		select {
+		case <-live.stepChan:
+			return true
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	return live.isPlaying.Load()
+}
+
+// toggleRecording 开始或停止录制带检测框的画面
+func (live *YOLOLiveWindow) toggleRecording() {
+	if live.stopRecording() {
+		return
+	}
+
+	path := fmt.Sprintf("recording_%s.mp4", time.Now().Format("20060102_150405"))
+	fps := live.fps
+	if fps <= 0 {
+		fps = 30
+	}
+
+	frames := make(chan image.Image, recordQueueSize)
+	live.recordMu.Lock()
+	live.recordFrames = frames
+	live.recordMu.Unlock()
+	go live.recordLoop(path, fps, frames)
+
+	fyne.Do(func() {
+		live.recordBtn.SetText("停止录制")
+		live.statusLabel.SetText(fmt.Sprintf("正在录制: %s", path))
+	})
+	fmt.Printf("🔴 开始录制: %s\n", path)
+}
+
+// recordFrame 将一帧带检测框的画面交给录制协程（未在录制时忽略）
+func (live *YOLOLiveWindow) recordFrame(img image.Image) {
+	live.recordMu.Lock()
+	defer live.recordMu.Unlock()
+
+	if live.recordFrames == nil {
+		return
+	}
+	select {
+	case live.recordFrames <- img:
+	default:
+		// 编码跟不上，丢弃本帧
+	}
+}
+
+// recordLoop 录制协程：首帧时按画面尺寸创建写入器，逐帧编码，队列关闭后完成文件并报告结果
+func (live *YOLOLiveWindow) recordLoop(path string, fps float64, frames chan image.Image) {
+	var writer *vidio.VideoWriter
+	var err error
+	for img := range frames {
+		if err != nil {
+			continue // 已失败，丢弃剩余的帧
+		}
+
+		if writer == nil {
+			bounds := img.Bounds()
+			writer, err = vidio.NewVideoWriter(path, bounds.Dx(), bounds.Dy(), &vidio.Options{
+				FPS:     fps,
+				Quality: 0.5,
+				Codec:   "libx264",
+			})
+			if err != nil {
+				err = fmt.Errorf("创建录制文件失败: %v", err)
+				live.detachRecording(frames)
+				continue
+			}
+		}
+
+		// 性能模式切换会改变显示尺寸，统一缩放到录制尺寸
+		width, height := writer.Width(), writer.Height()
+		if img.Bounds().Dx() != width || img.Bounds().Dy() != height {
+			img = imaging.Resize(img, width, height, imaging.Linear)
+		}
+
+		frame := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(frame, frame.Bounds(), img, img.Bounds().Min, draw.Src)
+		if werr := writer.Write(frame.Pix); werr != nil {
+			err = fmt.Errorf("写入录制帧失败: %v", werr)
+			live.detachRecording(frames)
+		}
+	}
+
+	if writer != nil {
+		writer.Close()
+	}
+
+	if err != nil {
+		fmt.Printf("❌ 录制失败: %v\n", err)
+		fyne.Do(func() {
+			live.recordBtn.SetText("录制")
+			live.statusLabel.SetText(fmt.Sprintf("录制失败: %v", err))
+		})
+		return
+	}
+	if writer == nil {
+		fyne.Do(func() {
+			live.statusLabel.SetText("录制已停止，没有录到画面")
+		})
+		return
+	}
+
+	fmt.Printf("⏹️ 录制完成: %s\n", path)
+	fyne.Do(func() {
+		live.statusLabel.SetText(fmt.Sprintf("录制已保存: %s", path))
+	})
+}
+
+// detachRecording 停止向frames投递新帧并关闭队列（frames已不是当前录制时不做处理）
+// 返回是否关闭了队列
+func (live *YOLOLiveWindow) detachRecording(frames chan image.Image) bool {
+	live.recordMu.Lock()
+	defer live.recordMu.Unlock()
+
+	if frames == nil || live.recordFrames != frames {
+		return false
+	}
+	close(frames)
+	live.recordFrames = nil
+	return true
+}
+
+// stopRecording 停止录制，剩余的帧由录制协程编码完成后报告结果
+// 返回调用前是否正在录制
+func (live *YOLOLiveWindow) stopRecording() bool {
+	live.recordMu.Lock()
+	frames := live.recordFrames
+	live.recordMu.Unlock()
+
+	if !live.detachRecording(frames) {
+		return false
+	}
+	if live.recordBtn != nil {
+		fyne.Do(func() {
+			live.recordBtn.SetText("录制")
+		})
+	}
+	return true
+}
+
 // stopPlayback 停止播放
 func (live *YOLOLiveWindow) stopPlayback() {
-	live.isPlaying = false
-	live.stopChan <- true
+	live.isPlaying.Store(false)
+	live.isPaused.Store(false)
+	live.stopRecording()
+	// 非阻塞发送停止信号：未开始播放或文件流没有接收方时不会卡住窗口关闭
+	select {
+	case live.stopChan <- true:
+	default:
+	}
 
 	fyne.Do(func() {
+		live.pauseBtn.SetText("暂停")
 		live.statusLabel.SetText("已停止")
 	})
 }
@@ -316,18 +596,8 @@ func (live *YOLOLiveWindow) stopPlayback() {
 // processVideo 处理视频
 func (live *YOLOLiveWindow) processVideo() {
 	// 设置检测器的运行时配置，确保使用正确的置信度和IOU阈值
-	detectionOptions := &yolo.DetectionOptions{
-		ConfThreshold: float32(live.confThreshold),
-		IOUThreshold:  float32(live.iouThreshold),
-		DrawBoxes:     live.drawBoxes,
-		DrawLabels:    live.drawLabels,
-		ShowFPS:       live.showFPS,
-		BoxColor:      live.boxColor,
-		LabelColor:    live.labelColor,
-		LineWidth:     live.lineWidth,
-		FontSize:      live.fontSize,
-	}
-	
+	detectionOptions := live.detectionOptions()
+
 	// 将配置设置到检测器中
 	live.detector.SetRuntimeConfig(detectionOptions)
 
@@ -344,25 +614,22 @@ func (live *YOLOLiveWindow) processVideo() {
 	// 启动UI更新协程
 	go func() {
 		for result := range detectionChan {
-			if !live.isPlaying {
+			if !live.isPlaying.Load() {
 				return
 			}
 
 			live.frameCount = result.frameNum
 			fmt.Printf("GUI更新协程收到第 %d 帧，图像尺寸: %dx%d\n", result.frameNum, result.img.Bounds().Dx(), result.img.Bounds().Dy())
 
-			// 计算FPS
-			elapsed := time.Since(live.startTime).Seconds()
-			if elapsed > 0 {
-				live.fps = float64(live.frameCount) / elapsed
-			}
+			// 计算FPS（滑动窗口平均，预热阶段为0）
+			live.fps = live.fpsMeter.Tick()
 
 			// 在主线程中更新UI
 			fyne.Do(func() {
 				fmt.Printf("开始更新GUI显示，帧号: %d\n", result.frameNum)
 				
-				// 更新FPS显示
-				if live.showFPS {
+				// 更新FPS显示（预热阶段保持上一次的显示）
+				if live.showFPS && live.fps > 0 {
 					live.fpsLabel.SetText(fmt.Sprintf("FPS: %.1f", live.fps))
 				}
 
@@ -373,6 +640,13 @@ func (live *YOLOLiveWindow) processVideo() {
 				// 更新显示
 				live.imageDisplay.Image = processedImage
 				live.imageDisplay.Refresh()
+
+				// 录制中则写入带检测框的画面
+				live.recordFrame(processedImage)
+
+				// 更新检测结果列表
+				live.currentDetections = result.detections
+				live.detectionList.Refresh()
 				fmt.Printf("GUI显示已更新，帧号: %d\n", result.frameNum)
 
 				// 更新状态
@@ -391,7 +665,7 @@ func (live *YOLOLiveWindow) processVideo() {
 		cameraProcessor := yolo.NewCameraVideoProcessor(live.detector, live.inputSource.Path)
 		
 		err := cameraProcessor.ProcessCameraWithCallback(func(result yolo.VideoDetectionResult) {
-			if !live.isPlaying {
+			if !live.isPlaying.Load() {
 				return
 			}
 
@@ -407,6 +681,11 @@ func (live *YOLOLiveWindow) processVideo() {
 				return
 			}
 
+			// 暂停时在此等待，单帧模式下每次只放行一帧
+			if !live.waitIfPaused() {
+				return
+			}
+
 			// 异步发送检测结果到UI更新协程
 			select {
 			case detectionChan <- struct{
@@ -433,7 +712,7 @@ func (live *YOLOLiveWindow) processVideo() {
 		inputPath := live.inputSource.GetFFmpegInput()
 		
 		_, err := live.detector.DetectFromRTSP(inputPath, detectionOptions, func(result yolo.VideoDetectionResult) {
-			if !live.isPlaying {
+			if !live.isPlaying.Load() {
 				return
 			}
 
@@ -444,6 +723,11 @@ func (live *YOLOLiveWindow) processVideo() {
 				return
 			}
 
+			// 暂停时在此等待，单帧模式下每次只放行一帧
+			if !live.waitIfPaused() {
+				return
+			}
+
 			// 异步发送检测结果到UI更新协程
 			select {
 			case detectionChan <- struct{
@@ -470,7 +754,7 @@ func (live *YOLOLiveWindow) processVideo() {
 		inputPath := live.inputSource.GetFFmpegInput()
 		
 		_, err := live.detector.DetectFromRTMP(inputPath, detectionOptions, func(result yolo.VideoDetectionResult) {
-			if !live.isPlaying {
+			if !live.isPlaying.Load() {
 				return
 			}
 
@@ -481,6 +765,11 @@ func (live *YOLOLiveWindow) processVideo() {
 				return
 			}
 
+			// 暂停时在此等待，单帧模式下每次只放行一帧
+			if !live.waitIfPaused() {
+				return
+			}
+
 			// 异步发送检测结果到UI更新协程
 			select {
 			case detectionChan <- struct{
@@ -508,7 +797,7 @@ func (live *YOLOLiveWindow) processVideo() {
 		inputPath := live.inputSource.GetFFmpegInput()
 
 		err := processor.ProcessVideoWithCallback(inputPath, func(result yolo.VideoDetectionResult) {
-			if !live.isPlaying {
+			if !live.isPlaying.Load() {
 				return
 			}
 
@@ -519,13 +808,14 @@ func (live *YOLOLiveWindow) processVideo() {
 				return
 			}
 
+			// 暂停时在此等待，单帧模式下每次只放行一帧
+			if !live.waitIfPaused() {
+				return
+			}
+
 			live.frameCount++
 
-			// 计算FPS
-			elapsed := time.Since(live.startTime).Seconds()
-			if elapsed > 0 {
-				live.fps = float64(live.frameCount) / elapsed
-			}
+			// FPS由UI更新协程按实际显示的帧统计，这里不重复计时
 
 			// 异步发送检测结果到UI更新协程
 			if result.Image != nil {
@@ -552,6 +842,37 @@ func (live *YOLOLiveWindow) processVideo() {
 	}
 }
 
+// SaveSnapshot 将当前显示的带检测框画面保存为带时间戳的PNG文件，不影响播放
+func (live *YOLOLiveWindow) SaveSnapshot() (string, error) {
+	img := live.imageDisplay.Image
+	if img == nil {
+		return "", fmt.Errorf("当前没有可保存的画面")
+	}
+
+	path := fmt.Sprintf("snapshot_%s.png", time.Now().Format("20060102_150405.000"))
+	if err := yolo.SaveImage(img, path); err != nil {
+		return "", err
+	}
+
+	fmt.Printf("📸 截图已保存: %s\n", path)
+	return path, nil
+}
+
+// detectionOptions 根据窗口当前配置生成检测选项
+func (live *YOLOLiveWindow) detectionOptions() *yolo.DetectionOptions {
+	return &yolo.DetectionOptions{
+		ConfThreshold: float32(live.confThreshold),
+		IOUThreshold:  float32(live.iouThreshold),
+		DrawBoxes:     live.drawBoxes,
+		DrawLabels:    live.drawLabels,
+		ShowFPS:       live.showFPS,
+		BoxColor:      live.boxColor,
+		LabelColor:    live.labelColor,
+		LineWidth:     live.lineWidth,
+		FontSize:      live.fontSize,
+	}
+}
+
 // drawDetectionsOnImage 在图像上绘制检测结果
 func (live *YOLOLiveWindow) drawDetectionsOnImage(img image.Image, detections []yolo.Detection) image.Image {
 	// 极限性能模式：移除调试输出以提升GUI响应速度
@@ -757,6 +1078,15 @@ func (live *YOLOLiveWindow) SetPerformanceMode(mode string) {
 		live.confThreshold = 0.25
 		live.iouThreshold = 0.45
 	}
+
+	// 同步阈值滑块显示
+	if live.confSlider != nil && live.iouSlider != nil {
+		conf, iou := live.confThreshold, live.iouThreshold
+		fyne.Do(func() {
+			live.confSlider.SetValue(conf)
+			live.iouSlider.SetValue(iou)
+		})
+	}
 }
 
 // GetPerformanceMode 获取当前性能模式
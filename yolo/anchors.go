@@ -0,0 +1,181 @@
+package yolo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// modelIONames 返回模型的输入输出名称（锚框模型有多个输出头，不能使用固定的 images/output0）
+func modelIONames(modelPath string) ([]string, []string, error) {
+	inputInfos, outputInfos, err := ort.GetInputOutputInfo(modelPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inputNames := make([]string, len(inputInfos))
+	for i, info := range inputInfos {
+		inputNames[i] = info.Name
+	}
+	outputNames := make([]string, len(outputInfos))
+	for i, info := range outputInfos {
+		outputNames[i] = info.Name
+	}
+	return inputNames, outputNames, nil
+}
+
+// inferAnchorGrid 运行锚框模型并解码所有输出头，返回模型输入尺寸坐标下的检测结果
+func (y *YOLO) inferAnchorGrid(inputTensor *ort.Tensor[float32], timings *Timings) ([]Detection, error) {
+	anchors := y.config.Anchors
+
+	// 输出由ONNX Runtime按实际形状分配
+	outputs := make([]ort.Value, len(anchors.Strides))
+	runStart := time.Now()
+	err := y.runSession([]ort.Value{inputTensor}, outputs)
+	timings.addInference(time.Since(runStart))
+	if err != nil {
+		if errors.Is(err, ErrInferenceTimeout) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInference, err)
+	}
+	defer func() {
+		for _, output := range outputs {
+			if output != nil {
+				output.Destroy()
+			}
+		}
+	}()
+
+	var detections []Detection
+	for head, output := range outputs {
+		tensor, ok := output.(*ort.Tensor[float32])
+		if !ok {
+			return nil, fmt.Errorf("输出头 %d 的张量类型不受支持: %T", head, output)
+		}
+		decoded, err := y.decodeAnchorHead(tensor.GetData(), tensor.GetShape(), head)
+		if err != nil {
+			return nil, err
+		}
+		detections = append(detections, decoded...)
+	}
+	if err := y.classCountError(); err != nil {
+		return nil, err
+	}
+	return detections, nil
+}
+
+// decodeAnchorHead 解码单个输出头，支持 [1, A*(5+C), H, W] 和 [1, A, H, W, 5+C] 两种形状
+func (y *YOLO) decodeAnchorHead(data []float32, shape []int64, head int) ([]Detection, error) {
+	cfg := y.config.Anchors
+	headAnchors := cfg.Anchors[head]
+	stride := float32(cfg.Strides[head])
+	numAnchors := len(headAnchors)
+
+	var gridH, gridW, numFeatures int
+	var index func(a, gy, gx, f int) int
+	switch len(shape) {
+	case 4:
+		gridH, gridW = int(shape[2]), int(shape[3])
+		numFeatures = int(shape[1]) / numAnchors
+		index = func(a, gy, gx, f int) int {
+			return (a*numFeatures+f)*gridH*gridW + gy*gridW + gx
+		}
+	case 5:
+		gridH, gridW, numFeatures = int(shape[2]), int(shape[3]), int(shape[4])
+		index = func(a, gy, gx, f int) int {
+			return ((a*gridH+gy)*gridW+gx)*numFeatures + f
+		}
+	default:
+		return nil, fmt.Errorf("输出头 %d 的形状不受支持: %v", head, shape)
+	}
+
+	numClasses := numFeatures - 5
+	if numClasses <= 0 || numFeatures*numAnchors*gridH*gridW != len(data) {
+		return nil, fmt.Errorf("输出头 %d 的形状 %v 与锚框数量 %d 不匹配", head, shape, numAnchors)
+	}
+	y.checkClassCount(numClasses)
+
+	activate := func(v float32) float32 {
+		if cfg.Sigmoid {
+			return sigmoid(v)
+		}
+		return v
+	}
+
+	confThreshold := y.confThreshold()
+	objThreshold := y.objectnessThreshold()
+	temperature := y.scoreTemperature()
+	ignored := y.ignoredClasses()
+
+	var detections []Detection
+	for a := 0; a < numAnchors; a++ {
+		anchorW, anchorH := headAnchors[a][0], headAnchors[a][1]
+		for gy := 0; gy < gridH; gy++ {
+			for gx := 0; gx < gridW; gx++ {
+				objectness := activate(data[index(a, gy, gx, 4)])
+				if objectness < objThreshold || objectness < confThreshold {
+					continue
+				}
+
+				var bestScore float32
+				bestID := 0
+				for c := 0; c < numClasses; c++ {
+					if score := calibrateScore(activate(data[index(a, gy, gx, 5+c)]), temperature); score > bestScore {
+						bestScore = score
+						bestID = c
+					}
+				}
+				score := objectness * bestScore
+				if score < confThreshold {
+					continue
+				}
+
+				tx := activate(data[index(a, gy, gx, 0)])
+				ty := activate(data[index(a, gy, gx, 1)])
+				tw := data[index(a, gy, gx, 2)]
+				th := data[index(a, gy, gx, 3)]
+
+				var cx, cy, w, h float32
+				if cfg.ScaledXY {
+					// YOLOv5/v7风格：xy = (2σ - 0.5 + grid) * stride，wh = (2σ)^2 * anchor
+					cx = (tx*2 - 0.5 + float32(gx)) * stride
+					cy = (ty*2 - 0.5 + float32(gy)) * stride
+					sw, sh := activate(tw)*2, activate(th)*2
+					w = sw * sw * anchorW
+					h = sh * sh * anchorH
+				} else {
+					// YOLOv3/v4风格：xy = (σ + grid) * stride，wh = exp(t) * anchor
+					cx = (tx + float32(gx)) * stride
+					cy = (ty + float32(gy)) * stride
+					w = float32(math.Exp(float64(tw))) * anchorW
+					h = float32(math.Exp(float64(th))) * anchorH
+				}
+
+				className := "unknown"
+				if bestID < len(globalClasses) {
+					className = globalClasses[bestID]
+				}
+				if ignored[className] {
+					continue
+				}
+
+				detections = append(detections, Detection{
+					Box:     [4]float32{cx - w/2, cy - h/2, cx + w/2, cy + h/2},
+					Score:   score,
+					ClassID: bestID,
+					Class:   className,
+				})
+			}
+		}
+	}
+	return detections, nil
+}
+
+// sigmoid 激活函数
+func sigmoid(v float32) float32 {
+	return float32(1 / (1 + math.Exp(-float64(v))))
+}
@@ -0,0 +1,90 @@
+package yolo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DetectionFuture 异步检测句柄
+type DetectionFuture struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	result *DetectionResults
+	err    error
+}
+
+// DetectAsync 异步检测，立即返回句柄，通过Wait获取结果
+// 复用VideoOptimization的工作许可、熔断器和性能指标；同一检测器上的异步检测串行执行，
+// 等待期间不要在该检测器上调用同步检测方法
+func (y *YOLO) DetectAsync(inputPath string, options *DetectionOptions) *DetectionFuture {
+	ctx, cancel := context.WithCancel(context.Background())
+	future := &DetectionFuture{
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	vo := y.GetVideoOptimization()
+	go func() {
+		defer close(future.done)
+		future.result, future.err = y.runAsyncDetection(ctx, vo, inputPath, options)
+	}()
+
+	return future
+}
+
+// runAsyncDetection 获取工作许可后执行检测
+func (y *YOLO) runAsyncDetection(ctx context.Context, vo *VideoOptimization, inputPath string, options *DetectionOptions) (*DetectionResults, error) {
+	select {
+	case <-vo.workerPool: // 获取工作许可
+	case <-ctx.Done():
+		return nil, ErrDetectionCanceled
+	case <-vo.ctx.Done():
+		return nil, fmt.Errorf("优化模块已关闭")
+	}
+	defer func() { vo.workerPool <- struct{}{} }()
+
+	if !vo.circuitBreakerAllow() {
+		return nil, fmt.Errorf("circuit breaker open")
+	}
+
+	y.asyncMu.Lock()
+	defer y.asyncMu.Unlock()
+
+	// 排队期间可能已被取消
+	if ctx.Err() != nil {
+		return nil, ErrDetectionCanceled
+	}
+
+	startTime := time.Now()
+	result, err := y.Detect(inputPath, options)
+	vo.updateMetrics(time.Since(startTime), err == nil)
+	vo.circuitBreakerRecord(err == nil)
+	return result, err
+}
+
+// Wait 阻塞直到检测完成或被取消
+func (f *DetectionFuture) Wait() (*DetectionResults, error) {
+	select {
+	case <-f.done:
+		// 完成与取消同时发生时以取消为准
+		if f.ctx.Err() != nil && f.err == nil {
+			return nil, ErrDetectionCanceled
+		}
+		return f.result, f.err
+	case <-f.ctx.Done():
+		return nil, ErrDetectionCanceled
+	}
+}
+
+// Done 返回检测完成时关闭的通道，便于与select配合使用
+func (f *DetectionFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Cancel 取消检测：尚未开始时不再执行，已开始时Wait立即返回ErrDetectionCanceled并丢弃结果
+func (f *DetectionFuture) Cancel() {
+	f.cancel()
+}
@@ -0,0 +1,105 @@
+package yolo
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// SupportsDynamicBatch 模型的批次维度是否为动态（-1），只有动态批次模型才能将多张图像合并为一个输入张量
+func (y *YOLO) SupportsDynamicBatch() bool {
+	return len(y.modelInputDims) == 4 && y.modelInputDims[0] <= 0
+}
+
+// DetectBatch 将多张图像合并为一个 [N, 3, H, W] 张量执行一次推理，结果按输入顺序返回
+// 批次维度固定的模型只能使用与之相同的批次大小，否则返回包装了ErrFixedBatchSize的错误
+func (y *YOLO) DetectBatch(images []image.Image) ([][]Detection, error) {
+	if len(images) == 0 {
+		return nil, nil
+	}
+	if !y.SupportsDynamicBatch() && len(y.modelInputDims) == 4 && y.modelInputDims[0] != int64(len(images)) {
+		return nil, fmt.Errorf("%w: 模型批次维度固定为 %d，无法一次推理 %d 张图像", ErrFixedBatchSize, y.modelInputDims[0], len(images))
+	}
+	if y.config.Anchors != nil {
+		return nil, fmt.Errorf("锚框模型暂不支持批量推理")
+	}
+
+	// 如果没有设置运行时配置，使用默认配置
+	if y.runtimeConfig == nil {
+		y.runtimeConfig = y.resolveOptions(nil)
+	}
+
+	inputWidth, inputHeight := y.config.InputWidth, y.config.InputHeight
+	if inputWidth <= 0 || inputHeight <= 0 {
+		inputWidth, inputHeight = y.config.InputSize, y.config.InputSize
+	}
+
+	// 逐张预处理并拼接为批次输入
+	imageSize := 3 * inputWidth * inputHeight
+	batchData := make([]float32, 0, imageSize*len(images))
+	for i, img := range images {
+		data, err := y.preprocessImageFromMemory(img)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 张图像预处理失败: %w", i, err)
+		}
+		batchData = append(batchData, data...)
+	}
+
+	inputTensor, err := ort.NewTensor(ort.NewShape(int64(len(images)), 3, int64(inputHeight), int64(inputWidth)), batchData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无法创建批次输入张量: %w", ErrInference, err)
+	}
+
+	// 输出由ONNX Runtime按实际形状分配；推理超时后张量由runSession在推理结束后释放
+	outputs := []ort.Value{nil}
+	if err := y.runSession([]ort.Value{inputTensor}, outputs); err != nil {
+		if errors.Is(err, ErrInferenceTimeout) {
+			return nil, err
+		}
+		inputTensor.Destroy()
+		destroyValues(outputs)
+		return nil, fmt.Errorf("%w: 批量推理: %w", ErrInference, err)
+	}
+	defer inputTensor.Destroy()
+	defer outputs[0].Destroy()
+
+	output, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		return nil, fmt.Errorf("输出张量类型不受支持: %T", outputs[0])
+	}
+	shape := output.GetShape()
+	if len(shape) != 3 || shape[0] != int64(len(images)) {
+		return nil, fmt.Errorf("批量输出形状 %v 与批次大小 %d 不匹配", shape, len(images))
+	}
+
+	// 按批次拆分输出，逐张解析、缩放并后处理
+	outputData := output.GetData()
+	perImage := int(shape[1] * shape[2])
+	imageShape := []int64{1, shape[1], shape[2]}
+	results := make([][]Detection, len(images))
+	for i, img := range images {
+		detections := y.parseDetections(outputData[i*perImage:(i+1)*perImage], imageShape)
+		if err := y.classCountError(); err != nil {
+			return nil, err
+		}
+
+		bounds := img.Bounds()
+		scaleX := float32(bounds.Dx()) / float32(inputWidth)
+		scaleY := float32(bounds.Dy()) / float32(inputHeight)
+		for j := range detections {
+			detections[j].Box[0] *= scaleX
+			detections[j].Box[1] *= scaleY
+			detections[j].Box[2] *= scaleX
+			detections[j].Box[3] *= scaleY
+		}
+
+		results[i], err = y.finalizeDetections(img, y.suppressOverlaps(detections))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
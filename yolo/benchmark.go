@@ -0,0 +1,136 @@
+package yolo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// benchmarkWarmupIterations 基准测试前的预热次数（不计入统计）
+const benchmarkWarmupIterations = 3
+
+// BenchmarkResult 推理基准测试结果
+type BenchmarkResult struct {
+	InputWidth  int           // 输入宽度
+	InputHeight int           // 输入高度
+	Iterations  int           // 计入统计的推理次数
+	Total       time.Duration // 总推理耗时
+	Mean        time.Duration // 平均延迟
+	Min         time.Duration // 最小延迟
+	Max         time.Duration // 最大延迟
+	P50         time.Duration // 50分位延迟
+	P95         time.Duration // 95分位延迟
+	P99         time.Duration // 99分位延迟
+	FPS         float64       // 按平均延迟计算的帧率
+}
+
+// String 返回基准测试结果摘要
+func (r BenchmarkResult) String() string {
+	return fmt.Sprintf("%dx%d x%d: mean=%v p50=%v p95=%v p99=%v min=%v max=%v fps=%.1f",
+		r.InputWidth, r.InputHeight, r.Iterations, r.Mean, r.P50, r.P95, r.P99, r.Min, r.Max, r.FPS)
+}
+
+// Benchmark 使用合成输入张量测量纯推理性能（不含图像解码、预处理和后处理）
+// inputSize 为正方形输入边长，<=0 时使用检测器配置的输入尺寸；固定输入尺寸的模型必须与之匹配
+// 先预热若干次，再执行 iterations 次推理并统计平均值和分位延迟；设置了InferenceTimeout时推理超时返回ErrInferenceTimeout
+func (y *YOLO) Benchmark(inputSize int, iterations int) (BenchmarkResult, error) {
+	if iterations <= 0 {
+		return BenchmarkResult{}, fmt.Errorf("迭代次数必须大于0")
+	}
+
+	width, height := inputSize, inputSize
+	if inputSize <= 0 {
+		if y.config.InputWidth > 0 && y.config.InputHeight > 0 {
+			width, height = y.config.InputWidth, y.config.InputHeight
+		} else {
+			width, height = y.config.InputSize, y.config.InputSize
+		}
+	}
+
+	// 合成输入：灰色图像
+	inputData := make([]float32, 3*width*height)
+	for i := range inputData {
+		inputData[i] = 0.5
+	}
+	inputTensor, err := ort.NewTensor(ort.NewShape(1, 3, int64(height), int64(width)), inputData)
+	if err != nil {
+		return BenchmarkResult{}, fmt.Errorf("无法创建输入张量: %v", err)
+	}
+	// 推理超时后输入张量由runSession在推理真正结束后释放
+	inputReleased := false
+	defer func() {
+		if !inputReleased {
+			inputTensor.Destroy()
+		}
+	}()
+
+	// 输出由ONNX Runtime按实际形状分配；经runSession运行，受InferenceTimeout约束
+	runOnce := func() (time.Duration, error) {
+		outputs := []ort.Value{nil}
+		start := time.Now()
+		err := y.runSession([]ort.Value{inputTensor}, outputs)
+		elapsed := time.Since(start)
+		if errors.Is(err, ErrInferenceTimeout) {
+			inputReleased = true
+			return elapsed, err
+		}
+		destroyValues(outputs)
+		return elapsed, err
+	}
+
+	fmt.Printf("⏱️ 基准测试: 输入 %dx%d, 预热 %d 次, 测试 %d 次\n", width, height, benchmarkWarmupIterations, iterations)
+
+	for i := 0; i < benchmarkWarmupIterations; i++ {
+		if _, err := runOnce(); err != nil {
+			return BenchmarkResult{}, fmt.Errorf("预热推理失败: %w", err)
+		}
+	}
+
+	latencies := make([]time.Duration, iterations)
+	var total time.Duration
+	for i := range latencies {
+		elapsed, err := runOnce()
+		if err != nil {
+			return BenchmarkResult{}, fmt.Errorf("推理失败: %w", err)
+		}
+		latencies[i] = elapsed
+		total += elapsed
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	result := BenchmarkResult{
+		InputWidth:  width,
+		InputHeight: height,
+		Iterations:  iterations,
+		Total:       total,
+		Mean:        total / time.Duration(iterations),
+		Min:         latencies[0],
+		Max:         latencies[iterations-1],
+		P50:         percentileDuration(latencies, 0.50),
+		P95:         percentileDuration(latencies, 0.95),
+		P99:         percentileDuration(latencies, 0.99),
+	}
+	if result.Mean > 0 {
+		result.FPS = float64(time.Second) / float64(result.Mean)
+	}
+
+	fmt.Printf("✅ 基准测试完成: %s\n", result)
+	return result, nil
+}
+
+// percentileDuration 计算已排序延迟的分位值（最近秩法）
+func percentileDuration(sorted []time.Duration, p float64) time.Duration {
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
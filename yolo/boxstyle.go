@@ -0,0 +1,148 @@
+package yolo
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/vector"
+)
+
+// boxArcSegments 圆角每个四分之一圆弧的折线段数
+const boxArcSegments = 8
+
+// strokeBox 使用抗锯齿矢量光栅化绘制检测框
+// 框的外边缘为 [x1, x2]×[y1, y2]（已限制在图像范围内），线条向内延伸lineWidth，各线段互不重叠；
+// radius>0时完整矩形样式绘制圆角
+func strokeBox(img draw.Image, x1, y1, x2, y2, lineWidth, radius float32, style BoxStyle, c color.Color) {
+	w, h := x2-x1, y2-y1
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	// 线宽不超过框的一半，避免内外边重叠
+	lw := minFloat32(lineWidth, minFloat32(w, h)/2)
+
+	// 光栅化器只覆盖检测框所在区域，坐标相对于该区域左上角
+	ox, oy := int(math.Floor(float64(x1))), int(math.Floor(float64(y1)))
+	bw, bh := int(math.Ceil(float64(x2)))-ox, int(math.Ceil(float64(y2)))-oy
+	z := vector.NewRasterizer(bw, bh)
+	x1, x2 = x1-float32(ox), x2-float32(ox)
+	y1, y2 = y1-float32(oy), y2-float32(oy)
+
+	switch style {
+	case BoxStyleCorners, BoxStyleDashed:
+		for _, segment := range boxSegments(x1, y1, x2, y2, lw, style) {
+			addRoundedRect(z, segment[0], segment[1], segment[2], segment[3], 0, false)
+		}
+	default:
+		// 外轮廓与反向的内轮廓组成环形，内部不填充
+		r := minFloat32(radius, minFloat32(w, h)/2)
+		addRoundedRect(z, x1, y1, x2, y2, r, false)
+		addRoundedRect(z, x1+lw, y1+lw, x2-lw, y2-lw, max(0, r-lw), true)
+	}
+
+	z.Draw(img, image.Rect(ox, oy, ox+bw, oy+bh), image.NewUniform(c), image.Point{})
+}
+
+// fillBox 使用指定不透明度的框颜色填充检测框区域，与原图进行alpha混合
+func fillBox(img draw.Image, x1, y1, x2, y2, radius float32, c color.Color, alpha uint8) {
+	w, h := x2-x1, y2-y1
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	ox, oy := int(math.Floor(float64(x1))), int(math.Floor(float64(y1)))
+	bw, bh := int(math.Ceil(float64(x2)))-ox, int(math.Ceil(float64(y2)))-oy
+	z := vector.NewRasterizer(bw, bh)
+	r := minFloat32(radius, minFloat32(w, h)/2)
+	addRoundedRect(z, x1-float32(ox), y1-float32(oy), x2-float32(ox), y2-float32(oy), r, false)
+
+	fill := color.NRGBAModel.Convert(c).(color.NRGBA)
+	fill.A = alpha
+	z.Draw(img, image.Rect(ox, oy, ox+bw, oy+bh), image.NewUniform(fill), image.Point{})
+}
+
+// boxSegments 将四角和虚线样式拆分为互不重叠的实心矩形 [x0, y0, x1, y1]
+func boxSegments(x1, y1, x2, y2, lw float32, style BoxStyle) [][4]float32 {
+	switch style {
+	case BoxStyleCorners:
+		// 每个角的L形边长为短边的1/4，且不短于两倍线宽
+		short := minFloat32(x2-x1, y2-y1)
+		length := minFloat32(max(short/4, 2*lw), short/2)
+		return [][4]float32{
+			// 左上
+			{x1, y1, x1 + length, y1 + lw},
+			{x1, y1 + lw, x1 + lw, y1 + length},
+			// 右上
+			{x2 - length, y1, x2, y1 + lw},
+			{x2 - lw, y1 + lw, x2, y1 + length},
+			// 左下
+			{x1, y2 - lw, x1 + length, y2},
+			{x1, y2 - length, x1 + lw, y2 - lw},
+			// 右下
+			{x2 - length, y2 - lw, x2, y2},
+			{x2 - lw, y2 - length, x2, y2 - lw},
+		}
+
+	case BoxStyleDashed:
+		dash := 4*lw + 4
+		gap := dash / 2
+		var segments [][4]float32
+		for x := x1; x < x2; x += dash + gap {
+			end := minFloat32(x+dash, x2)
+			segments = append(segments, [4]float32{x, y1, end, y1 + lw}, [4]float32{x, y2 - lw, end, y2})
+		}
+		for y := y1 + lw; y < y2-lw; y += dash + gap {
+			end := minFloat32(y+dash, y2-lw)
+			segments = append(segments, [4]float32{x1, y, x1 + lw, end}, [4]float32{x2 - lw, y, x2, end})
+		}
+		return segments
+	}
+	return nil
+}
+
+// addRoundedRect 向光栅化器添加（圆角）矩形轮廓，reverse为true时反向绘制，用于挖空内部
+func addRoundedRect(z *vector.Rasterizer, x0, y0, x1, y1, r float32, reverse bool) {
+	if x1 <= x0 || y1 <= y0 {
+		return
+	}
+
+	// 顺时针依次为右上、右下、左下、左上四个圆角的圆心和起始角
+	corners := [4]struct {
+		cx, cy, start float64
+	}{
+		{float64(x1 - r), float64(y0 + r), -math.Pi / 2},
+		{float64(x1 - r), float64(y1 - r), 0},
+		{float64(x0 + r), float64(y1 - r), math.Pi / 2},
+		{float64(x0 + r), float64(y0 + r), math.Pi},
+	}
+
+	var points [][2]float32
+	for _, corner := range corners {
+		if r <= 0 {
+			points = append(points, [2]float32{float32(corner.cx), float32(corner.cy)})
+			continue
+		}
+		for i := 0; i <= boxArcSegments; i++ {
+			angle := corner.start + math.Pi/2*float64(i)/boxArcSegments
+			points = append(points, [2]float32{
+				float32(corner.cx + float64(r)*math.Cos(angle)),
+				float32(corner.cy + float64(r)*math.Sin(angle)),
+			})
+		}
+	}
+
+	if reverse {
+		for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+			points[i], points[j] = points[j], points[i]
+		}
+	}
+
+	z.MoveTo(points[0][0], points[0][1])
+	for _, p := range points[1:] {
+		z.LineTo(p[0], p[1])
+	}
+	z.ClosePath()
+}
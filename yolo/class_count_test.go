@@ -0,0 +1,69 @@
+package yolo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// cocoLikeClasses 返回n个占位类别名
+func cocoLikeClasses(n int) []string {
+	classes := make([]string, n)
+	for i := range classes {
+		classes[i] = fmt.Sprintf("class%d", i)
+	}
+	return classes
+}
+
+// TestClassCountMismatchFromOutput 非80类模型的输出能被解析，并报告类别数不一致
+func TestClassCountMismatchFromOutput(t *testing.T) {
+	previous := GetClasses()
+	defer SetClasses(previous)
+	SetClasses(cocoLikeClasses(80))
+
+	// 2个类别的特征在前输出 [1, 6, 8]（候选框数多于特征数）
+	const numBoxes = 8
+	output := make([]float32, 6*numBoxes)
+	for i := 0; i < numBoxes; i++ {
+		output[0*numBoxes+i] = 100 // cx
+		output[1*numBoxes+i] = 100 // cy
+		output[2*numBoxes+i] = 20  // w
+		output[3*numBoxes+i] = 20  // h
+		output[4*numBoxes+i] = 0.9 // class0
+	}
+
+	y := &YOLO{config: &YOLOConfig{StrictClasses: true}}
+	detections := y.parseDetections(output, []int64{1, 6, numBoxes})
+	if len(detections) != numBoxes {
+		t.Fatalf("期望解析出 %d 个检测框，实际 %d", numBoxes, len(detections))
+	}
+	if err := y.classCountError(); !errors.Is(err, ErrClassCountMismatch) {
+		t.Fatalf("期望ErrClassCountMismatch，实际: %v", err)
+	}
+}
+
+// TestDeclaredClassCount 加载阶段根据声明的输出形状推算类别数
+func TestDeclaredClassCount(t *testing.T) {
+	previous := GetClasses()
+	defer SetClasses(previous)
+	SetClasses(cocoLikeClasses(80))
+
+	tests := []struct {
+		shape []int64
+		want  int
+		ok    bool
+	}{
+		{[]int64{1, 84, 8400}, 80, true},
+		{[]int64{1, 6, 8400}, 2, true},
+		{[]int64{1, 8400, 6}, 2, true},
+		{[]int64{1, 25200, 85}, 80, true},
+		{[]int64{1, -1, -1}, 0, false},
+	}
+	for _, tt := range tests {
+		y := &YOLO{config: &YOLOConfig{}, modelOutputShape: tt.shape}
+		got, ok := y.declaredClassCount()
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("形状 %v: 期望 (%d, %v)，实际 (%d, %v)", tt.shape, tt.want, tt.ok, got, ok)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package yolo
+
+// applyClassMapping 按WithClassMapping将细分类别合并为粗粒度类别，并在每个合并组内重新执行NMS
+// 合并后的检测结果保留原始ClassID，未出现在映射中的类别保持不变
+func (y *YOLO) applyClassMapping(detections []Detection) []Detection {
+	if y.runtimeConfig == nil || len(y.runtimeConfig.ClassMapping) == 0 || len(detections) == 0 {
+		return detections
+	}
+	mapping := y.runtimeConfig.ClassMapping
+
+	groups := make(map[string][]Detection)
+	var order []string
+	result := make([]Detection, 0, len(detections))
+	for _, det := range detections {
+		target, ok := mapping[det.Class]
+		if !ok {
+			result = append(result, det)
+			continue
+		}
+		det.Class = target
+		if _, exists := groups[target]; !exists {
+			order = append(order, target)
+		}
+		groups[target] = append(groups[target], det)
+	}
+
+	// 原本属于不同类别的框合并后可能重叠（如同一目标同时被识别为car和truck），组内只保留最高分的框
+	iouThreshold := y.iouThreshold()
+	for _, target := range order {
+		result = append(result, y.nonMaxSuppression(groups[target], iouThreshold)...)
+	}
+	return result
+}
@@ -2,8 +2,12 @@ package yolo
 
 import (
 	"fmt"
+	"image"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/disintegration/imaging"
 )
 
 // YOLOConfig YOLO检测器配置（检测器级别 - 创建时设置）
@@ -15,25 +19,143 @@ type YOLOConfig struct {
 	GPUDeviceID int    // GPU设备ID（默认0，仅在UseGPU=true时有效）
 	LibraryPath string // ONNX Runtime库路径
 	AutoCreateConfig bool // 是否自动创建配置文件（默认false）
+	ModelPath   string // 模型文件路径（NewYOLO未传入模型路径时使用）
+	ClassPath   string // 类别配置文件路径（NewYOLO未传入配置路径时使用）
+	AutoOrient  bool   // 是否按EXIF方向自动旋转图片（手机竖拍照片）
+	ResizeFilter ResizeFilter // 预处理缩放插值算法（默认Lanczos）
+	MaxPreprocessDimension int // 预处理时源图像最长边上限（像素），超过时先等比例缩小，0表示不限制
+	Quiet       bool   // 静默模式：不输出逐帧进度和调试信息
+	StrictClasses bool // 类别文件和模型元数据都没有类别时NewYOLO返回错误（默认回退到COCO类别）
 	// CUDA加速配置
 	UseCUDA      bool   // 是否使用CUDA加速（需要CUDA库支持）
 	CUDADeviceID int    // CUDA设备ID（默认0，仅在UseCUDA=true时有效）
 	CUDAMemoryPool bool // 是否启用CUDA内存池优化（默认true）
+	// 内存管理配置（应用到检测器共享的优化实例，0表示使用默认值）
+	GCInterval    int64 // 垃圾回收间隔（每N帧清理一次）
+	MemoryLimitMB int64 // 内存上限（MB），超过时触发资源保护
+	Parallelism   ParallelismOptions // 优化实例的工作线程数、批处理大小和队列容量（0表示按CPU核数自动计算）
+	KeepWarmInterval time.Duration // 空闲超过该间隔时运行一次丢弃结果的推理，防止GPU降频（0表示不启用）
+	// 视频解码配置
+	HWAccel string // FFmpeg硬件解码方式（cuda/qsv/videotoolbox等，空表示软件解码）
+	FramePTS bool  // 通过ffprobe读取视频每帧的实际PTS作为时间戳（可变帧率视频），默认按 帧号/FPS 计算
+	// 模型输出配置
+	OutputLayout OutputLayout  // 输出张量布局（默认自动判断）
+	Anchors      *AnchorConfig // 锚框解码配置（仅用于输出原始网格的模型，nil表示无锚框的v8风格输出）
+}
+
+// AnchorConfig 基于锚框的原始网格输出解码配置（未导出解码层的YOLOv3/v4/v7等模型）
+// 每个输出头按模型输出顺序对应一个步长和一组锚框
+type AnchorConfig struct {
+	Strides  []int          // 每个输出头的步长，如 [8, 16, 32]
+	Anchors  [][][2]float32 // 每个输出头的锚框宽高（像素），如 {{{10, 13}, {16, 30}, {33, 23}}, ...}
+	Sigmoid  bool           // 输出是否为未激活的logits，需要对xy、置信度和类别概率应用sigmoid
+	ScaledXY bool           // 是否使用YOLOv5/v7风格的 2σ-0.5 中心点和 (2σ)² 宽高解码（否则为v3/v4风格）
+}
+
+// OutputLayout 模型输出张量布局
+type OutputLayout string
+
+const (
+	OutputLayoutAuto          OutputLayout = ""               // 自动判断（较大的维度为候选框数量）
+	OutputLayoutFeaturesFirst OutputLayout = "features_first" // [batch, features, detections]，YOLOv8默认导出
+	OutputLayoutFeaturesLast  OutputLayout = "features_last"  // [batch, detections, features]，YOLOv5及部分转置导出
+)
+
+// ResizeFilter 预处理缩放插值算法，质量从高到低、速度从慢到快依次为Lanczos、Linear、Box、NearestNeighbor
+// 所有算法都直接缩放到模型输入尺寸，坐标换算方式相同
+type ResizeFilter string
+
+const (
+	ResizeFilterLanczos         ResizeFilter = ""        // Lanczos（默认，质量最高）
+	ResizeFilterLinear          ResizeFilter = "linear"  // 双线性
+	ResizeFilterBox             ResizeFilter = "box"     // 盒式滤波
+	ResizeFilterNearestNeighbor ResizeFilter = "nearest" // 最近邻（最快，适合实时视频）
+)
+
+// resample 返回对应的imaging插值滤波器
+func (f ResizeFilter) resample() imaging.ResampleFilter {
+	switch f {
+	case ResizeFilterLinear:
+		return imaging.Linear
+	case ResizeFilterBox:
+		return imaging.Box
+	case ResizeFilterNearestNeighbor:
+		return imaging.NearestNeighbor
+	default:
+		return imaging.Lanczos
+	}
 }
 
+// BoxStyle 检测框绘制样式
+type BoxStyle string
+
+const (
+	BoxStyleFull    BoxStyle = ""        // 完整矩形（默认）
+	BoxStyleCorners BoxStyle = "corners" // 仅绘制四个L形角，适合目标密集的画面
+	BoxStyleDashed  BoxStyle = "dashed"  // 虚线矩形
+)
+
+// LabelPosition 标签相对检测框的位置
+type LabelPosition string
+
+const (
+	LabelPositionAuto      LabelPosition = ""           // 框上方，超出上边界时改为框下方（默认）
+	LabelPositionAbove     LabelPosition = "above"      // 始终在框上方（受图像边界限制）
+	LabelPositionBelow     LabelPosition = "below"      // 始终在框下方（受图像边界限制）
+	LabelPositionInsideTop LabelPosition = "inside-top" // 框内顶部
+)
+
 // DetectionOptions 检测选项
 type DetectionOptions struct {
-	ConfThreshold float32 // 置信度阈值
-	IOUThreshold  float32 // IOU阈值
-	DrawBoxes     bool    // 是否绘制检测框
-	DrawLabels    bool    // 是否绘制标签
-	ShowFPS       bool    // 是否显示FPS
-	BoxColor      string  // 检测框颜色
-	LabelColor    string  // 标签颜色
-	LineWidth     int     // 线条宽度
-	FontSize      int     // 字体大小
+	ConfThreshold float32       // 置信度阈值
+	IOUThreshold  float32       // IOU阈值
+	DrawBoxes     bool          // 是否绘制检测框
+	DrawLabels    bool          // 是否绘制标签
+	ShowFPS       bool          // 是否显示FPS
+	BoxColor      string        // 检测框颜色
+	LabelColor    string        // 标签颜色
+	LineWidth     int           // 线条宽度
+	FontSize      int           // 字体大小
+	TrailLength   int           // 跟踪轨迹长度（最近N帧的中心点，0表示不绘制）
+	Zones         *ZoneCounter  // 区域计数器（每处理一帧更新一次计数，绘制时叠加区域轮廓和计数）
+	UseWBF        bool          // 使用加权框融合（WBF）代替NMS合并重叠框
+	UseTTA        bool          // 测试时增强：额外推理水平翻转图像并合并结果（约2倍计算量）
+	PostProcessor PostProcessor // 检测后处理器（对每个检测结果的裁剪区域调用）
+	ClampBoxes    bool          // 将返回的检测框坐标裁剪到图像范围内
+	DropOffscreen bool          // 丢弃中心点在画面外的检测结果
+	NMSFunc       NMSFunc       // 自定义NMS（设置后代替内置NMS和WBF）
+
+	ObjectnessThreshold float32           // 目标置信度阈值（YOLOv5风格输出，与类别概率相乘前过滤）
+	ClassColors         map[string]string // 按类别名设置检测框颜色（未设置的类别使用BoxColor）
+	ShowLegend          bool              // 是否在右上角绘制类别图例（颜色、类别名、数量）
+	TimestampOverlay    bool              // 是否在视频帧左下角叠加时间戳（视频时间或实时流的系统时间）
+	MinAspectRatio      float32           // 最小宽高比（宽/高），<=0表示不限制
+	MaxAspectRatio      float32           // 最大宽高比（宽/高），<=0表示不限制
+	TopK                int               // 每个检测结果保留的候选类别数（Detection.TopClasses），0表示不保留
+	MotionThreshold     float64           // 运动门控阈值（帧间平均灰度差0-1），低于该值时复用上一帧结果，0表示不启用
+	CropRegion          image.Rectangle   // 仅检测该区域（原图坐标），结果映射回全图坐标；空矩形表示检测整幅图像
+	ScoreTemperature    float32           // 类别分数温度缩放系数（>1降低、<1提高置信度），0或1表示不缩放
+	BoxStyle            BoxStyle          // 检测框样式（完整矩形、四角或虚线）
+	BoxCornerRadius     float32           // 检测框圆角半径（像素，仅完整矩形样式），0表示直角
+	BoxFillAlpha        uint8             // 检测框半透明填充的不透明度（0-255），0表示不填充
+	LabelPosition       LabelPosition     // 标签位置（框上方、框下方或框内顶部）
+	LabelTemplate       string            // 标签模板，如 "{class} {score:.0%}"，空表示 "类别 分数"
+	ScoreFormat         string            // 置信度显示格式（".2f"为两位小数，".0%"为整数百分比），空表示".2f"
+	IgnoreClasses       []string          // 忽略的类别名（解析输出时直接丢弃，如模型经常误检的类别）
+	PartialResults      bool              // 视频读取中途失败时返回已完成帧的结果和ErrPartialResults错误
+	InferenceTimeout    time.Duration     // 单帧推理超时时间，超时的帧按无检测结果处理，0表示不限制
+	ClassMapping        map[string]string // 类别合并映射（细分类别名 -> 输出类别名），如 car/truck/bus -> vehicle
+	FPSWindow           int               // FPS叠加的滑动平均窗口（帧数），0表示默认30帧
+	FPSWarmupFrames     int               // 开始显示FPS前忽略的帧数，0表示默认5帧
 }
 
+// NMSFunc 自定义非极大抑制函数，输入原始候选框和IOU阈值，返回保留的检测结果
+type NMSFunc func(detections []Detection, iouThreshold float32) []Detection
+
+// PostProcessor 检测后处理器，img为检测框裁剪出的区域
+// 可运行二级分类器修改Detection.Class或写入Detection.Attributes
+type PostProcessor func(img image.Image, d *Detection)
+
 // DefaultConfig 返回默认极限性能配置（检测器级别）
 // 现在集成了自动模型检测功能
 func DefaultConfig() *YOLOConfig {
@@ -100,6 +222,52 @@ func (c *YOLOConfig) WithInputDimensions(width, height int) *YOLOConfig {
 	return c
 }
 
+// WithModelPath 设置模型文件路径
+func (c *YOLOConfig) WithModelPath(path string) *YOLOConfig {
+	c.ModelPath = path
+	return c
+}
+
+// WithClassPath 设置类别配置文件路径（如data.yaml）
+func (c *YOLOConfig) WithClassPath(path string) *YOLOConfig {
+	c.ClassPath = path
+	return c
+}
+
+// WithAutoOrient 设置是否按EXIF方向自动旋转图片
+func (c *YOLOConfig) WithAutoOrient(autoOrient bool) *YOLOConfig {
+	c.AutoOrient = autoOrient
+	return c
+}
+
+// WithQuiet 设置静默模式，处理长视频时不输出逐帧进度、输出解析和标签绘制等调试信息
+func (c *YOLOConfig) WithQuiet(quiet bool) *YOLOConfig {
+	c.Quiet = quiet
+	return c
+}
+
+// WithStrictClasses 设置严格类别模式，类别文件和模型元数据都没有类别信息时NewYOLO直接失败，避免自定义模型被误标为COCO类别
+// 首次推理发现模型类别数与类别列表不一致时，检测返回ErrClassCountMismatch（非严格模式仅输出警告）
+func (c *YOLOConfig) WithStrictClasses(strict bool) *YOLOConfig {
+	c.StrictClasses = strict
+	return c
+}
+
+// WithResizeFilter 设置预处理缩放插值算法，视频场景可用Linear或NearestNeighbor换取预处理速度
+func (c *YOLOConfig) WithResizeFilter(filter ResizeFilter) *YOLOConfig {
+	c.ResizeFilter = filter
+	return c
+}
+
+// WithMaxPreprocessDimension 设置预处理时源图像最长边上限（像素，不能小于模型输入尺寸），默认0表示不限制
+// 超大图像（如8000px扫描件）会先用区域平均快速缩小到该尺寸再缩放到模型输入尺寸，并输出一次警告；
+// 检测框坐标仍映射回原图。注意解码后的原图仍完整驻留内存（50MP的RGBA图像约200MB），
+// 预处理本身只额外分配中间尺寸和模型输入尺寸的缓冲区
+func (c *YOLOConfig) WithMaxPreprocessDimension(px int) *YOLOConfig {
+	c.MaxPreprocessDimension = px
+	return c
+}
+
 // WithAutoCreateConfig 设置是否自动创建配置文件
 func (c *YOLOConfig) WithAutoCreateConfig(autoCreate bool) *YOLOConfig {
 	c.AutoCreateConfig = autoCreate
@@ -230,11 +398,147 @@ func (c *YOLOConfig) WithLibraryPath(path string) *YOLOConfig {
 	return c
 }
 
+// WithGCInterval 设置垃圾回收间隔（每N帧清理一次）
+func (c *YOLOConfig) WithGCInterval(frames int64) *YOLOConfig {
+	c.GCInterval = frames
+	return c
+}
+
+// WithMemoryLimitMB 设置内存上限（MB）
+func (c *YOLOConfig) WithMemoryLimitMB(limitMB int64) *YOLOConfig {
+	c.MemoryLimitMB = limitMB
+	return c
+}
+
+// WithParallelism 显式设置优化实例的工作线程数、批处理大小和队列容量（0表示自动计算）
+// 在CPU受限的容器中可避免按宿主机核数创建过多工作协程
+func (c *YOLOConfig) WithParallelism(workers, batchSize, queueSize int) *YOLOConfig {
+	c.Parallelism = ParallelismOptions{
+		Workers:   workers,
+		BatchSize: batchSize,
+		QueueSize: queueSize,
+	}
+	return c
+}
+
+// WithHWAccel 设置视频硬件解码方式（cuda、qsv、videotoolbox、vaapi、d3d11va、dxva2、auto）
+// 硬件解码不可用时自动回退到软件解码；不支持的取值由NewYOLO校验配置时返回ErrInvalidConfig
+func (c *YOLOConfig) WithHWAccel(kind string) *YOLOConfig {
+	c.HWAccel = strings.ToLower(kind)
+	return c
+}
+
+// WithFramePTS 启用后视频帧时间戳取自ffprobe读取的实际PTS，而不是按 帧号/FPS 计算
+// 可变帧率（如手机录制）视频按帧率计算的时间戳会逐渐偏离，影响时间戳叠加和事件日志；需要安装FFmpeg
+func (c *YOLOConfig) WithFramePTS(enable bool) *YOLOConfig {
+	c.FramePTS = enable
+	return c
+}
+
+// WithKeepWarm 启用空闲保温：检测器空闲超过interval时在后台运行一次丢弃结果的推理
+// 避免GPU空闲降频后下一次推理明显变慢，适合对延迟敏感的常驻服务；Close时停止
+func (c *YOLOConfig) WithKeepWarm(interval time.Duration) *YOLOConfig {
+	c.KeepWarmInterval = interval
+	return c
+}
+
+// Validate 检查配置是否合法（NewYOLO创建检测器前调用），返回第一个发现的问题
+func (c *YOLOConfig) Validate() error {
+	if c.InputSize < 0 {
+		return fmt.Errorf("输入尺寸不能为负数: %d", c.InputSize)
+	}
+	if c.InputWidth < 0 || c.InputHeight < 0 {
+		return fmt.Errorf("输入宽高不能为负数: %dx%d", c.InputWidth, c.InputHeight)
+	}
+	if (c.InputWidth > 0) != (c.InputHeight > 0) {
+		return fmt.Errorf("输入宽度和高度必须同时设置: %dx%d", c.InputWidth, c.InputHeight)
+	}
+	if c.InputSize == 0 && c.InputWidth == 0 {
+		return fmt.Errorf("未设置输入尺寸，请使用 WithInputSize 或 WithInputDimensions")
+	}
+	if c.InputSize > 0 && c.InputWidth > 0 && (c.InputWidth != c.InputSize || c.InputHeight != c.InputSize) {
+		return fmt.Errorf("同时设置了正方形输入尺寸 %d 和非正方形尺寸 %dx%d，请只使用其中一种",
+			c.InputSize, c.InputWidth, c.InputHeight)
+	}
+	if c.GPUDeviceID < 0 {
+		return fmt.Errorf("GPU设备ID不能为负数: %d", c.GPUDeviceID)
+	}
+	if c.CUDADeviceID < 0 {
+		return fmt.Errorf("CUDA设备ID不能为负数: %d", c.CUDADeviceID)
+	}
+	if c.GCInterval < 0 {
+		return fmt.Errorf("GC间隔不能为负数: %d", c.GCInterval)
+	}
+	if c.MemoryLimitMB < 0 {
+		return fmt.Errorf("内存上限不能为负数: %d", c.MemoryLimitMB)
+	}
+	if p := c.Parallelism; p.Workers < 0 || p.BatchSize < 0 || p.MaxBatchSize < 0 || p.QueueSize < 0 {
+		return fmt.Errorf("并行度设置不能为负数: %+v", p)
+	}
+	if c.KeepWarmInterval < 0 {
+		return fmt.Errorf("保温间隔不能为负数: %v", c.KeepWarmInterval)
+	}
+	if c.MaxPreprocessDimension < 0 {
+		return fmt.Errorf("预处理尺寸上限不能为负数: %d", c.MaxPreprocessDimension)
+	}
+	if c.MaxPreprocessDimension > 0 {
+		inputMax := c.InputSize
+		if c.InputWidth > inputMax {
+			inputMax = c.InputWidth
+		}
+		if c.InputHeight > inputMax {
+			inputMax = c.InputHeight
+		}
+		if c.MaxPreprocessDimension < inputMax {
+			return fmt.Errorf("预处理尺寸上限 %d 不能小于模型输入尺寸 %d", c.MaxPreprocessDimension, inputMax)
+		}
+	}
+	switch c.ResizeFilter {
+	case ResizeFilterLanczos, ResizeFilterLinear, ResizeFilterBox, ResizeFilterNearestNeighbor:
+	default:
+		return fmt.Errorf("未知的缩放插值算法: %q", c.ResizeFilter)
+	}
+	switch c.OutputLayout {
+	case OutputLayoutAuto, OutputLayoutFeaturesFirst, OutputLayoutFeaturesLast:
+	default:
+		return fmt.Errorf("未知的输出布局: %q", c.OutputLayout)
+	}
+	if c.Anchors != nil {
+		if len(c.Anchors.Strides) == 0 || len(c.Anchors.Strides) != len(c.Anchors.Anchors) {
+			return fmt.Errorf("锚框配置的步长数量 %d 与锚框组数量 %d 不一致", len(c.Anchors.Strides), len(c.Anchors.Anchors))
+		}
+		for i, stride := range c.Anchors.Strides {
+			if stride <= 0 || len(c.Anchors.Anchors[i]) == 0 {
+				return fmt.Errorf("锚框配置的第 %d 个输出头无效（步长 %d，锚框 %d 个）", i, stride, len(c.Anchors.Anchors[i]))
+			}
+		}
+	}
+	return validateHWAccel(c.HWAccel)
+}
+
+// WithAnchors 设置锚框解码配置（模型输出为多尺度原始网格时使用）
+func (c *YOLOConfig) WithAnchors(anchors *AnchorConfig) *YOLOConfig {
+	c.Anchors = anchors
+	return c
+}
+
+// WithOutputLayout 设置模型输出张量布局（自动判断出错时显式指定）
+func (c *YOLOConfig) WithOutputLayout(layout OutputLayout) *YOLOConfig {
+	c.OutputLayout = layout
+	return c
+}
+
+// 默认检测阈值（未设置运行时配置或阈值为0时使用）
+const (
+	DefaultConfThreshold float32 = 0.4 // 默认置信度阈值
+	DefaultIOUThreshold  float32 = 0.5 // 默认NMS IOU阈值
+)
+
 // DefaultDetectionOptions 默认检测选项（运行时级别）
 func DefaultDetectionOptions() *DetectionOptions {
 	return &DetectionOptions{
-		ConfThreshold: 0.4,
-		IOUThreshold:  0.5,
+		ConfThreshold: DefaultConfThreshold,
+		IOUThreshold:  DefaultIOUThreshold,
 		DrawBoxes:     true,
 		DrawLabels:    true,
 		ShowFPS:       false,
@@ -287,6 +591,50 @@ func (o *DetectionOptions) WithLabelColor(color string) *DetectionOptions {
 	return o
 }
 
+// WithBoxStyle 设置检测框样式（BoxStyleFull、BoxStyleCorners、BoxStyleDashed）
+func (o *DetectionOptions) WithBoxStyle(style BoxStyle) *DetectionOptions {
+	o.BoxStyle = style
+	return o
+}
+
+// WithBoxCornerRadius 设置检测框圆角半径（像素，仅对完整矩形样式生效）
+func (o *DetectionOptions) WithBoxCornerRadius(radius float32) *DetectionOptions {
+	o.BoxCornerRadius = radius
+	return o
+}
+
+// WithBoxFillAlpha 设置检测框半透明填充（使用框颜色与原图混合，0表示不填充，255表示完全覆盖）
+func (o *DetectionOptions) WithBoxFillAlpha(alpha uint8) *DetectionOptions {
+	o.BoxFillAlpha = alpha
+	return o
+}
+
+// WithLabelPosition 设置标签位置（LabelPositionAbove、LabelPositionBelow、LabelPositionInsideTop）
+func (o *DetectionOptions) WithLabelPosition(pos LabelPosition) *DetectionOptions {
+	o.LabelPosition = pos
+	return o
+}
+
+// WithLabelTemplate 设置标签模板
+// 支持占位符 {class} {class_id} {score} {id}（跟踪ID） {area}（像素面积），score可带格式如 {score:.0%}
+func (o *DetectionOptions) WithLabelTemplate(template string) *DetectionOptions {
+	o.LabelTemplate = template
+	return o
+}
+
+// WithScoreFormat 设置置信度显示的小数位数和是否显示为百分比
+func (o *DetectionOptions) WithScoreFormat(decimals int, percent bool) *DetectionOptions {
+	if decimals < 0 {
+		decimals = 0
+	}
+	suffix := "f"
+	if percent {
+		suffix = "%"
+	}
+	o.ScoreFormat = fmt.Sprintf(".%d%s", decimals, suffix)
+	return o
+}
+
 // WithLineWidth 设置线条宽度
 func (o *DetectionOptions) WithLineWidth(width int) *DetectionOptions {
 	o.LineWidth = width
@@ -299,6 +647,151 @@ func (o *DetectionOptions) WithFontSize(size int) *DetectionOptions {
 	return o
 }
 
+// WithDrawTrails 设置跟踪轨迹长度（绘制每个TrackID最近length帧的中心点连线）
+func (o *DetectionOptions) WithDrawTrails(length int) *DetectionOptions {
+	o.TrailLength = length
+	return o
+}
+
+// WithZones 设置区域计数器，每处理一帧更新一次计数（目标由帧间跟踪分配TrackID），绘制检测结果时叠加显示
+func (o *DetectionOptions) WithZones(zones *ZoneCounter) *DetectionOptions {
+	o.Zones = zones
+	return o
+}
+
+// WithWBF 设置是否使用加权框融合（WBF）代替NMS
+func (o *DetectionOptions) WithWBF(enable bool) *DetectionOptions {
+	o.UseWBF = enable
+	return o
+}
+
+// WithClassColor 设置指定类别的检测框颜色（同时用于图例）
+func (o *DetectionOptions) WithClassColor(class, color string) *DetectionOptions {
+	if o.ClassColors == nil {
+		o.ClassColors = make(map[string]string)
+	}
+	o.ClassColors[class] = color
+	return o
+}
+
+// WithLegend 设置是否绘制类别图例
+func (o *DetectionOptions) WithLegend(enable bool) *DetectionOptions {
+	o.ShowLegend = enable
+	return o
+}
+
+// WithMotionGate 设置运动门控阈值：视频帧与上次检测帧的平均灰度差（0-1）低于阈值时跳过推理并复用上次结果
+// 适合大部分时间静止的监控画面，建议从0.01左右开始调整
+func (o *DetectionOptions) WithMotionGate(threshold float64) *DetectionOptions {
+	o.MotionThreshold = threshold
+	return o
+}
+
+// WithScoreTemperature 设置类别分数温度缩放，在阈值过滤前按 sigmoid(logit(p)/t) 校准置信度
+// 用于置信度整体偏高（t>1）或偏低（t<1）的自定义模型，无需重新训练
+func (o *DetectionOptions) WithScoreTemperature(t float32) *DetectionOptions {
+	o.ScoreTemperature = t
+	return o
+}
+
+// WithCropRegion 设置只检测图像中的指定区域，模型看到的是放大后的裁剪区域，可提高已知区域内小目标的召回率
+func (o *DetectionOptions) WithCropRegion(rect image.Rectangle) *DetectionOptions {
+	o.CropRegion = rect
+	return o
+}
+
+// WithTopClasses 设置每个检测结果返回得分最高的K个类别（多标签或易混淆类别场景）
+// 检测结果的Class/Score仍为最高分类别
+func (o *DetectionOptions) WithTopClasses(k int) *DetectionOptions {
+	o.TopK = k
+	return o
+}
+
+// WithAspectRatioRange 设置检测框宽高比（宽/高）范围，超出范围的检测框在NMS前被丢弃
+// 例如行人检测可设置 WithAspectRatioRange(0.2, 1.0) 过滤比高还宽的"人"
+func (o *DetectionOptions) WithAspectRatioRange(min, max float32) *DetectionOptions {
+	o.MinAspectRatio = min
+	o.MaxAspectRatio = max
+	return o
+}
+
+// WithTimestampOverlay 设置是否在保存/标注的视频帧上叠加时间戳
+func (o *DetectionOptions) WithTimestampOverlay(enable bool) *DetectionOptions {
+	o.TimestampOverlay = enable
+	return o
+}
+
+// WithPartialResults 视频读取中途失败（解码中断）时，Detect同时返回已完成帧的DetectionResults和
+// 包装了ErrPartialResults的错误（可用errors.Is判断），避免长时间任务在接近结束时失败而丢失全部结果。
+// 单帧推理失败不会中断视频，该帧按空检测结果继续处理
+func (o *DetectionOptions) WithPartialResults(enable bool) *DetectionOptions {
+	o.PartialResults = enable
+	return o
+}
+
+// WithInferenceTimeout 设置单帧推理超时时间，防止偶发的GPU卡死阻塞整个视频
+// 超时后检测返回ErrInferenceTimeout，视频中该帧按无检测结果处理；超时的推理结束前后续帧也直接按超时处理
+func (o *DetectionOptions) WithInferenceTimeout(timeout time.Duration) *DetectionOptions {
+	o.InferenceTimeout = timeout
+	return o
+}
+
+// WithClassMapping 设置类别合并映射，输出时将细分类别改名为粗粒度类别（如 "car"、"truck"、"bus" -> "vehicle"）
+// 合并后在同一输出类别内重新执行NMS，去除原本属于不同类别的重叠框
+func (o *DetectionOptions) WithClassMapping(mapping map[string]string) *DetectionOptions {
+	o.ClassMapping = mapping
+	return o
+}
+
+// WithFPSWindow 设置FPS叠加的滑动平均窗口和预热帧数，避免开头几帧的帧率剧烈跳动
+func (o *DetectionOptions) WithFPSWindow(window, warmupFrames int) *DetectionOptions {
+	o.FPSWindow = window
+	o.FPSWarmupFrames = warmupFrames
+	return o
+}
+
+// WithIgnoreClasses 设置忽略的类别（按类别名），最佳类别属于这些类别的检测结果在解析时直接丢弃
+func (o *DetectionOptions) WithIgnoreClasses(classes []string) *DetectionOptions {
+	o.IgnoreClasses = classes
+	return o
+}
+
+// WithObjectnessThreshold 设置目标置信度阈值（仅对YOLOv5风格输出生效，独立于ConfThreshold）
+func (o *DetectionOptions) WithObjectnessThreshold(threshold float32) *DetectionOptions {
+	o.ObjectnessThreshold = threshold
+	return o
+}
+
+// WithNMSFunc 设置自定义NMS（如DIoU-NMS、Cluster-NMS），为nil时恢复内置NMS
+func (o *DetectionOptions) WithNMSFunc(fn func([]Detection, float32) []Detection) *DetectionOptions {
+	o.NMSFunc = fn
+	return o
+}
+
+// WithClampBoxes 设置是否将返回的检测框坐标裁剪到图像范围内（不仅限于绘制）
+func (o *DetectionOptions) WithClampBoxes(enable bool) *DetectionOptions {
+	o.ClampBoxes = enable
+	return o
+}
+
+// WithDropOffscreen 设置是否丢弃中心点在画面外的检测结果
+func (o *DetectionOptions) WithDropOffscreen(enable bool) *DetectionOptions {
+	o.DropOffscreen = enable
+	return o
+}
+
+// WithPostProcessor 设置检测后处理器（如车牌识别、物种分类等二级模型）
+func (o *DetectionOptions) WithPostProcessor(fn func(img image.Image, d *Detection)) *DetectionOptions {
+	o.PostProcessor = fn
+	return o
+}
+
+// WithTTA 设置是否启用测试时增强（水平翻转）
+func (o *DetectionOptions) WithTTA(enable bool) *DetectionOptions {
+	o.UseTTA = enable
+	return o
+}
+
 // HighPerformanceConfig 高性能配置（自动检测并优化CPU/GPU）
 // 注意：DefaultConfig现在已经是高性能配置，此函数保持向后兼容
 func HighPerformanceConfig() *YOLOConfig {
@@ -0,0 +1,70 @@
+package yolo
+
+import (
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	effectiveCPUsOnce  sync.Once
+	effectiveCPUsValue int
+)
+
+// EffectiveCPUs 返回进程实际可用的CPU数，用于确定工作线程数和推理线程数
+// 取 GOMAXPROCS 与cgroup CPU配额（Kubernetes/Docker的CPU limit，向上取整）中的较小值；
+// runtime.NumCPU 在容器中返回宿主机核数，按其计算会创建过多工作协程。结果在首次调用后缓存
+func EffectiveCPUs() int {
+	effectiveCPUsOnce.Do(func() {
+		cpus := runtime.GOMAXPROCS(0)
+		if quota, ok := cgroupCPUQuota(); ok {
+			if limit := int(math.Ceil(quota)); limit < cpus {
+				cpus = limit
+			}
+		}
+		if cpus < 1 {
+			cpus = 1
+		}
+		effectiveCPUsValue = cpus
+	})
+	return effectiveCPUsValue
+}
+
+// cgroupCPUQuota 读取cgroup的CPU配额（核数），未设置限制或非Linux时返回false
+func cgroupCPUQuota() (float64, bool) {
+	// cgroup v2: cpu.max 内容为 "<quota> <period>" 或 "max <period>"
+	if data, err := os.ReadFile("/sys/fs/cgroup/cpu.max"); err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) == 2 && fields[0] != "max" {
+			return parseCPUQuota(fields[0], fields[1])
+		}
+		return 0, false
+	}
+
+	// cgroup v1: cpu.cfs_quota_us 为-1表示不限制
+	quota, err := os.ReadFile("/sys/fs/cgroup/cpu/cpu.cfs_quota_us")
+	if err != nil {
+		return 0, false
+	}
+	period, err := os.ReadFile("/sys/fs/cgroup/cpu/cpu.cfs_period_us")
+	if err != nil {
+		return 0, false
+	}
+	return parseCPUQuota(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+// parseCPUQuota 将配额和周期（微秒）换算为核数
+func parseCPUQuota(quotaText, periodText string) (float64, bool) {
+	quota, err := strconv.ParseFloat(quotaText, 64)
+	if err != nil || quota <= 0 {
+		return 0, false
+	}
+	period, err := strconv.ParseFloat(periodText, 64)
+	if err != nil || period <= 0 {
+		return 0, false
+	}
+	return quota / period, true
+}
@@ -0,0 +1,47 @@
+package yolo
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/disintegration/imaging"
+)
+
+// cropRegion 返回与图像相交的裁剪区域，未设置裁剪区域时ok为false
+func (y *YOLO) cropRegion(img image.Image) (image.Rectangle, bool, error) {
+	if y.runtimeConfig == nil || y.runtimeConfig.CropRegion == (image.Rectangle{}) {
+		return image.Rectangle{}, false, nil
+	}
+
+	region := y.runtimeConfig.CropRegion.Canon().Intersect(img.Bounds())
+	if region.Empty() {
+		return image.Rectangle{}, false, fmt.Errorf("裁剪区域 %v 与图像范围 %v 不相交", y.runtimeConfig.CropRegion, img.Bounds())
+	}
+	return region, true, nil
+}
+
+// detectCropRegion 只检测裁剪区域，并将检测框映射回全图坐标
+func (y *YOLO) detectCropRegion(img image.Image, region image.Rectangle, timings *Timings) ([]Detection, error) {
+	// 裁剪后的图像原点为(0, 0)，推理和后处理都在裁剪区域坐标下进行
+	cropped := imaging.Crop(img, region)
+
+	options := *y.runtimeConfig
+	options.CropRegion = image.Rectangle{}
+	saved := y.runtimeConfig
+	y.runtimeConfig = &options
+	detections, err := y.runDetection(cropped, timings)
+	y.runtimeConfig = saved
+	if err != nil {
+		return nil, err
+	}
+
+	offsetX := float32(region.Min.X - img.Bounds().Min.X)
+	offsetY := float32(region.Min.Y - img.Bounds().Min.Y)
+	for i := range detections {
+		detections[i].Box[0] += offsetX
+		detections[i].Box[1] += offsetY
+		detections[i].Box[2] += offsetX
+		detections[i].Box[3] += offsetY
+	}
+	return detections, nil
+}
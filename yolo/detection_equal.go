@@ -0,0 +1,55 @@
+package yolo
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+// Equal 判断两个检测结果是否相同：类别和跟踪ID一致，检测框坐标和分数的差值都不超过tol
+// 不比较附加属性和候选类别
+func (d Detection) Equal(other Detection, tol float32) bool {
+	if d.ClassID != other.ClassID || d.Class != other.Class || d.TrackID != other.TrackID {
+		return false
+	}
+	if absFloat32(d.Score-other.Score) > tol {
+		return false
+	}
+	for i := range d.Box {
+		if absFloat32(d.Box[i]-other.Box[i]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash 返回检测结果的稳定哈希（FNV-64a），用于去重和缓存
+// 检测框坐标按整数像素、分数按千分之一量化后参与计算，同一输入多次运行的结果哈希一致；
+// 量化边界两侧的微小差异仍可能得到不同哈希，需要容差比较时使用Equal
+func (d Detection) Hash() uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+
+	writeInt := func(v int64) {
+		binary.LittleEndian.PutUint64(buf[:], uint64(v))
+		h.Write(buf[:])
+	}
+
+	h.Write([]byte(d.Class))
+	h.Write([]byte{0})
+	writeInt(int64(d.ClassID))
+	writeInt(int64(d.TrackID))
+	for _, v := range d.Box {
+		writeInt(int64(math.Round(float64(v))))
+	}
+	writeInt(int64(math.Round(float64(d.Score) * 1000)))
+	return h.Sum64()
+}
+
+// absFloat32 返回float32的绝对值
+func absFloat32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
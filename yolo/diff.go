@@ -0,0 +1,53 @@
+package yolo
+
+import "sort"
+
+// DiffDetections 比较两帧检测结果，找出新出现、消失和明显移动的目标（无需跟踪器）
+// 同类别的框按IoU从高到低一对一匹配：无法匹配的当前帧目标为appeared，无法匹配的上一帧目标为disappeared；
+// 匹配成功但IoU低于iouThreshold的当前帧目标为moved。移动过快导致两帧框完全不重叠时视为一次消失加一次出现
+func DiffDetections(prev, cur []Detection, iouThreshold float32) (appeared, disappeared, moved []Detection) {
+	type candidate struct {
+		prev, cur int
+		iou       float32
+	}
+
+	var candidates []candidate
+	for i, p := range prev {
+		for j, c := range cur {
+			if p.Class != c.Class || p.ClassID != c.ClassID {
+				continue
+			}
+			if iou := boxIOU(p.Box, c.Box); iou > 0 {
+				candidates = append(candidates, candidate{prev: i, cur: j, iou: iou})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].iou > candidates[j].iou
+	})
+
+	prevMatched := make([]bool, len(prev))
+	curMatched := make([]bool, len(cur))
+	for _, c := range candidates {
+		if prevMatched[c.prev] || curMatched[c.cur] {
+			continue
+		}
+		prevMatched[c.prev] = true
+		curMatched[c.cur] = true
+		if c.iou < iouThreshold {
+			moved = append(moved, cur[c.cur])
+		}
+	}
+
+	for j, c := range cur {
+		if !curMatched[j] {
+			appeared = append(appeared, c)
+		}
+	}
+	for i, p := range prev {
+		if !prevMatched[i] {
+			disappeared = append(disappeared, p)
+		}
+	}
+	return appeared, disappeared, moved
+}
@@ -0,0 +1,99 @@
+package yolo
+
+import "fmt"
+
+// Ensemble 多模型集成检测器
+// 对同一图像运行所有模型并用MergeDetections合并结果，例如通用COCO模型 + 专用缺陷模型
+type Ensemble struct {
+	members      []ensembleMember
+	IOUThreshold float32 // 合并时的NMS IOU阈值
+}
+
+// ensembleMember 集成中的单个模型
+type ensembleMember struct {
+	name     string
+	detector *YOLO
+	classes  []string // 该模型的类别列表（按ClassID索引）
+	idOffset int      // 合并后ClassID的偏移量，避免不同模型的类别索引冲突
+}
+
+// NewEnsemble 创建多模型集成检测器
+func NewEnsemble(iouThreshold float32) *Ensemble {
+	if iouThreshold <= 0 {
+		iouThreshold = DefaultIOUThreshold
+	}
+	return &Ensemble{IOUThreshold: iouThreshold}
+}
+
+// Add 添加模型，name用于类别命名空间（结果类别为 "name/class"）
+// classes为该模型的类别列表；为nil时使用当前全局类别列表。
+// 由于类别列表是全局的，后加载的模型会覆盖之前的类别，多个模型类别不同时请显式传入
+func (e *Ensemble) Add(name string, detector *YOLO, classes []string) *Ensemble {
+	if classes == nil {
+		classes = append([]string(nil), GetClasses()...)
+	}
+
+	offset := 0
+	for _, member := range e.members {
+		offset += len(member.classes)
+	}
+
+	e.members = append(e.members, ensembleMember{
+		name:     name,
+		detector: detector,
+		classes:  classes,
+		idOffset: offset,
+	})
+	return e
+}
+
+// Classes 返回合并后的类别列表（带命名空间，按合并后的ClassID索引）
+func (e *Ensemble) Classes() []string {
+	var classes []string
+	for _, member := range e.members {
+		for _, class := range member.classes {
+			classes = append(classes, member.name+"/"+class)
+		}
+	}
+	return classes
+}
+
+// DetectImage 使用所有模型检测图像并合并结果
+func (e *Ensemble) DetectImage(imagePath string) ([]Detection, error) {
+	if len(e.members) == 0 {
+		return nil, fmt.Errorf("集成检测器中没有模型")
+	}
+
+	sets := make([][]Detection, 0, len(e.members))
+	for _, member := range e.members {
+		detections, err := member.detector.DetectImage(imagePath)
+		if err != nil {
+			return nil, fmt.Errorf("模型 %s 检测失败: %v", member.name, err)
+		}
+		sets = append(sets, member.namespace(detections))
+	}
+
+	return MergeDetections(e.IOUThreshold, sets...), nil
+}
+
+// Close 关闭所有模型
+func (e *Ensemble) Close() {
+	for _, member := range e.members {
+		member.detector.Close()
+	}
+}
+
+// namespace 为检测结果添加模型命名空间并偏移ClassID
+func (m ensembleMember) namespace(detections []Detection) []Detection {
+	result := make([]Detection, len(detections))
+	for i, det := range detections {
+		class := det.Class
+		if det.ClassID >= 0 && det.ClassID < len(m.classes) {
+			class = m.classes[det.ClassID]
+		}
+		det.Class = m.name + "/" + class
+		det.ClassID += m.idOffset
+		result[i] = det
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package yolo
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// 哨兵错误，调用方可通过errors.Is区分错误类型（例如服务端据此返回400或500）
+var (
+	// ErrModelLoad 模型或ONNX Runtime加载失败
+	ErrModelLoad = errors.New("模型加载失败")
+	// ErrInference 推理执行失败
+	ErrInference = errors.New("推理失败")
+	// ErrUnsupportedFormat 输入文件或模型文件格式不受支持
+	ErrUnsupportedFormat = errors.New("不支持的文件格式")
+	// ErrInvalidInput 输入图像无法读取或解码
+	ErrInvalidInput = errors.New("无效的输入")
+	// ErrGPUUnavailable GPU执行提供者不可用或初始化失败
+	ErrGPUUnavailable = errors.New("GPU不可用")
+	// ErrInvalidConfig 检测器配置无效（YOLOConfig.Validate未通过）
+	ErrInvalidConfig = errors.New("配置无效")
+)
+
+// ErrUnsupportedModelFormat 模型文件格式不受支持（当前仅支持ONNX），同时匹配ErrUnsupportedFormat
+var ErrUnsupportedModelFormat = fmt.Errorf("%w（模型）", ErrUnsupportedFormat)
+
+// ErrClassCountMismatch 模型输出的类别数与类别配置文件不一致
+var ErrClassCountMismatch = errors.New("模型类别数与类别列表不一致")
+
+// ErrFixedBatchSize 模型批次维度固定，无法按请求的数量批量推理
+var ErrFixedBatchSize = errors.New("模型不支持动态批次")
+
+// ErrDetectionCanceled 异步检测在完成前被取消
+var ErrDetectionCanceled = errors.New("检测已取消")
+
+// ErrPartialResults 视频检测中途失败，返回的DetectionResults只包含已完成的帧（需启用WithPartialResults）
+var ErrPartialResults = errors.New("检测中断，仅返回部分结果")
+
+// ErrInferenceTimeout 推理超过DetectionOptions.InferenceTimeout仍未返回，同时匹配ErrInference
+var ErrInferenceTimeout = fmt.Errorf("%w: 推理超时", ErrInference)
+
+// ErrRuntimeChecksum 下载的ONNX Runtime发布包缺少固定校验值或SHA-256不匹配，同时匹配ErrModelLoad
+var ErrRuntimeChecksum = fmt.Errorf("%w: ONNX Runtime发布包校验失败", ErrModelLoad)
+
+// knownModelFormats 常见的非ONNX模型格式及其说明
+var knownModelFormats = map[string]string{
+	".pt":          "PyTorch",
+	".pth":         "PyTorch",
+	".torchscript": "TorchScript",
+	".engine":      "TensorRT",
+	".trt":         "TensorRT",
+	".tflite":      "TensorFlow Lite",
+	".pb":          "TensorFlow",
+	".mlmodel":     "CoreML",
+	".mlpackage":   "CoreML",
+	".xml":         "OpenVINO",
+	".bin":         "OpenVINO",
+	".param":       "NCNN",
+}
+
+// checkModelFormat 根据扩展名检查模型格式，非ONNX时返回包装了ErrUnsupportedModelFormat的错误
+func checkModelFormat(modelPath string) error {
+	ext := strings.ToLower(filepath.Ext(modelPath))
+	if ext == ".onnx" {
+		return nil
+	}
+
+	if format, ok := knownModelFormats[ext]; ok {
+		return fmt.Errorf("%w: 仅支持.onnx模型，实际为%s模型(%s)，请先导出为ONNX格式",
+			ErrUnsupportedModelFormat, format, ext)
+	}
+	if ext == "" {
+		return fmt.Errorf("%w: 仅支持.onnx模型，文件无扩展名: %s", ErrUnsupportedModelFormat, modelPath)
+	}
+	return fmt.Errorf("%w: 仅支持.onnx模型，实际为%s", ErrUnsupportedModelFormat, ext)
+}
@@ -0,0 +1,140 @@
+package yolo
+
+import (
+	"fmt"
+	"sort"
+)
+
+// EvalIOUThreshold 评估时检测框与真值框匹配的IoU阈值
+const EvalIOUThreshold = 0.5
+
+// ThresholdMetrics 某个置信度阈值下的检测统计
+// 未提供真值时只有Detections有效
+type ThresholdMetrics struct {
+	Threshold      float32
+	Detections     int // 检测结果总数
+	TruePositives  int
+	FalsePositives int
+	FalseNegatives int
+	Precision      float64
+	Recall         float64
+	F1             float64
+}
+
+// String 返回统计摘要
+func (m ThresholdMetrics) String() string {
+	return fmt.Sprintf("conf=%.2f detections=%d P=%.3f R=%.3f F1=%.3f",
+		m.Threshold, m.Detections, m.Precision, m.Recall, m.F1)
+}
+
+// DefaultEvalThresholds 默认扫描的置信度阈值（0.05-0.95，步长0.05）
+func DefaultEvalThresholds() []float32 {
+	thresholds := make([]float32, 0, 19)
+	for i := 1; i <= 19; i++ {
+		thresholds = append(thresholds, float32(i)*0.05)
+	}
+	return thresholds
+}
+
+// EvaluateThresholds 在一组图片上按多个置信度阈值统计检测效果，帮助选择ConfThreshold
+// thresholds为nil时使用DefaultEvalThresholds；groundTruth为图片路径到真值框（Class和Box）的映射，
+// 提供时计算每个阈值的精确率、召回率和F1（同类别IoU≥EvalIOUThreshold视为命中），未提供时只统计检测数量。
+// 每张图片只以最低阈值检测一次，再按分数过滤得到各阈值的结果（贪心NMS下与逐阈值检测等价）
+func (y *YOLO) EvaluateThresholds(images []string, thresholds []float32, groundTruth ...map[string][]Detection) (map[float32]ThresholdMetrics, error) {
+	if len(images) == 0 {
+		return nil, fmt.Errorf("%w: 评估图片列表为空", ErrInvalidInput)
+	}
+	if len(thresholds) == 0 {
+		thresholds = DefaultEvalThresholds()
+	}
+
+	var truth map[string][]Detection
+	if len(groundTruth) > 0 {
+		truth = groundTruth[0]
+	}
+
+	lowest := thresholds[0]
+	for _, t := range thresholds {
+		if t < lowest {
+			lowest = t
+		}
+	}
+
+	// 以最低阈值检测，关闭运动门控（图片之间无关联）
+	previous := y.runtimeConfig
+	opts := *y.resolveOptions(previous)
+	opts.ConfThreshold = lowest
+	opts.MotionThreshold = 0
+	y.runtimeConfig = &opts
+	defer func() { y.runtimeConfig = previous }()
+
+	metrics := make(map[float32]ThresholdMetrics, len(thresholds))
+	for _, t := range thresholds {
+		metrics[t] = ThresholdMetrics{Threshold: t}
+	}
+
+	for i, path := range images {
+		detections, err := y.DetectImage(path)
+		if err != nil {
+			return nil, fmt.Errorf("评估图片 %s 检测失败: %w", path, err)
+		}
+		y.verbosef("📊 评估进度 %d/%d: %s, %d 个候选\n", i+1, len(images), path, len(detections))
+
+		for _, t := range thresholds {
+			kept := FilterDetections(detections).MinScore(t).Detections()
+			m := metrics[t]
+			m.Detections += len(kept)
+			if truth != nil {
+				tp := matchGroundTruth(kept, truth[path], EvalIOUThreshold)
+				m.TruePositives += tp
+				m.FalsePositives += len(kept) - tp
+				m.FalseNegatives += len(truth[path]) - tp
+			}
+			metrics[t] = m
+		}
+	}
+
+	if truth != nil {
+		for t, m := range metrics {
+			if m.TruePositives+m.FalsePositives > 0 {
+				m.Precision = float64(m.TruePositives) / float64(m.TruePositives+m.FalsePositives)
+			}
+			if m.TruePositives+m.FalseNegatives > 0 {
+				m.Recall = float64(m.TruePositives) / float64(m.TruePositives+m.FalseNegatives)
+			}
+			if m.Precision+m.Recall > 0 {
+				m.F1 = 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+			}
+			metrics[t] = m
+		}
+	}
+
+	return metrics, nil
+}
+
+// matchGroundTruth 按分数从高到低将检测结果与同类别真值框一对一匹配，返回命中数
+func matchGroundTruth(detections, truth []Detection, iouThreshold float32) int {
+	sorted := append([]Detection(nil), detections...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Score > sorted[j].Score
+	})
+
+	matched := make([]bool, len(truth))
+	hits := 0
+	for _, det := range sorted {
+		best, bestIoU := -1, iouThreshold
+		for i, gt := range truth {
+			if matched[i] || gt.Class != det.Class {
+				continue
+			}
+			if iou := boxIOU(det.Box, gt.Box); iou >= bestIoU {
+				best, bestIoU = i, iou
+			}
+		}
+		if best >= 0 {
+			matched[best] = true
+			hits++
+		}
+	}
+	return hits
+}
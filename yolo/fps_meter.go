@@ -0,0 +1,101 @@
+package yolo
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFPSWindow 帧率滑动平均窗口（帧数）
+	DefaultFPSWindow = 30
+	// DefaultFPSWarmupFrames 开始报告帧率前忽略的帧数（预热阶段耗时不稳定）
+	DefaultFPSWarmupFrames = 5
+	// fpsResetGap 两帧间隔超过该值（如新视频开始）时重新统计
+	fpsResetGap = 2 * time.Second
+)
+
+// FPSMeter 按最近若干帧的滑动窗口统计帧率，预热阶段返回0
+// 避免开头几帧因模型预热和极小的耗时导致帧率剧烈跳动；零值使用默认窗口和预热帧数
+type FPSMeter struct {
+	mu     sync.Mutex
+	window int
+	warmup int
+	frames int         // 本轮统计已记录的帧数
+	times  []time.Time // 窗口内各帧的时间（最多window+1个）
+}
+
+// NewFPSMeter 创建帧率统计器，window为平均窗口帧数，warmupFrames为开始报告前忽略的帧数（<=0使用默认值）
+func NewFPSMeter(window, warmupFrames int) *FPSMeter {
+	m := &FPSMeter{}
+	m.SetWindow(window, warmupFrames)
+	return m
+}
+
+// SetWindow 设置平均窗口和预热帧数（<=0使用默认值），不清空已有统计
+func (m *FPSMeter) SetWindow(window, warmupFrames int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.window = window
+	m.warmup = warmupFrames
+}
+
+// Tick 记录一帧并返回窗口内的平均帧率，预热阶段或样本不足时返回0
+func (m *FPSMeter) Tick() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if n := len(m.times); n > 0 && now.Sub(m.times[n-1]) > fpsResetGap {
+		m.frames = 0
+		m.times = m.times[:0]
+	}
+	m.frames++
+
+	window, warmup := m.window, m.warmup
+	if window <= 0 {
+		window = DefaultFPSWindow
+	}
+	if warmup <= 0 {
+		warmup = DefaultFPSWarmupFrames
+	}
+
+	// 预热帧不计入窗口，只保留最后一帧的时间作为起点
+	if m.frames <= warmup {
+		m.times = append(m.times[:0], now)
+		return 0
+	}
+
+	m.times = append(m.times, now)
+	if len(m.times) > window+1 {
+		m.times = append(m.times[:0], m.times[len(m.times)-window-1:]...)
+	}
+	return m.fpsLocked()
+}
+
+// FPS 返回当前窗口内的平均帧率（不记录新帧）
+func (m *FPSMeter) FPS() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.fpsLocked()
+}
+
+// Reset 清空统计，下一帧重新开始预热
+func (m *FPSMeter) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.frames = 0
+	m.times = m.times[:0]
+}
+
+// fpsLocked 计算窗口内的平均帧率（调用方须持有锁）
+func (m *FPSMeter) fpsLocked() float64 {
+	n := len(m.times)
+	if n < 2 {
+		return 0
+	}
+	elapsed := m.times[n-1].Sub(m.times[0])
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(n-1) * float64(time.Second) / float64(elapsed)
+}
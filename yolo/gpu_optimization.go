@@ -3,7 +3,6 @@ package yolo
 import (
 	"context"
 	"fmt"
-	"runtime"
 	"sync"
 	"time"
 )
@@ -45,7 +44,7 @@ func NewHighEndGPUVideoOptimization() *VideoOptimization {
 // NewAdaptiveGPUVideoOptimization 创建自适应GPU视频优化实例
 // 根据检测到的显存大小自动调整批处理和内存池配置
 func NewAdaptiveGPUVideoOptimization() *VideoOptimization {
-	cpuCores := runtime.NumCPU()
+	cpuCores := EffectiveCPUs()
 
 	// 检测显存大小（简化版本，实际应该通过CUDA API获取）
 	vramGB := detectVRAMSize() // 假设这个函数存在
@@ -163,7 +162,7 @@ func NewAdaptiveGPUVideoOptimization() *VideoOptimization {
 
 // NewHighPerformanceGPUVideoOptimization 创建高性能GPU专用视频优化实例
 func NewHighPerformanceGPUVideoOptimization() *VideoOptimization {
-	cpuCores := runtime.NumCPU()
+	cpuCores := EffectiveCPUs()
 
 	// 高性能GPU专用配置 - 充分利用大显存
 	batchSize := cpuCores * 8       // 大批处理，利用大显存
@@ -274,7 +273,8 @@ func HighEndGPUPerformanceTips() {
 	fmt.Println("7. 关闭不必要的后台程序释放显存")
 	fmt.Println("8. 使用 TensorRT 进一步优化模型")
 	fmt.Println("9. 监控GPU利用率，确保达到90%+")
-	fmt.Println("10. 考虑使用混合精度(FP16)提升性能\n")
+	fmt.Println("10. 考虑使用混合精度(FP16)提升性能")
+	fmt.Println()
 }
 
 // HighPerformanceGPUTips 高性能GPU性能优化建议（向后兼容）
@@ -285,7 +285,7 @@ func HighPerformanceGPUTips() {
 // GetGPUBenchmarkConfig 获取GPU基准测试配置
 // 根据显存大小返回相应的性能预期
 func GetGPUBenchmarkConfig(vramGB int) map[string]interface{} {
-	cpuCores := runtime.NumCPU()
+	cpuCores := EffectiveCPUs()
 
 	switch {
 	case vramGB >= 20: // 高端GPU, 大显存
@@ -0,0 +1,65 @@
+package yolo
+
+import "math"
+
+// IdentityHomography 单位单应矩阵（坐标不变，用作参考相机）
+var IdentityHomography = [9]float32{1, 0, 0, 0, 1, 0, 0, 0, 1}
+
+// CameraView 单个相机视角的检测结果及其到公共坐标系的单应矩阵
+type CameraView struct {
+	Detections []Detection
+	Homography [9]float32 // 行优先的3x3矩阵，将该相机的像素坐标映射到公共坐标系
+}
+
+// TransformDetections 使用单应矩阵将检测结果变换到公共坐标系（如全景图或俯视平面图）
+// 检测框的四个角分别变换后取外接矩形。
+// 任一角点映射到无穷远（位于地平线之后）的检测结果会被丢弃。不修改原切片
+func TransformDetections(dets []Detection, homography [9]float32) []Detection {
+	result := make([]Detection, 0, len(dets))
+	for _, det := range dets {
+		corners := [4][2]float32{
+			{det.Box[0], det.Box[1]},
+			{det.Box[2], det.Box[1]},
+			{det.Box[2], det.Box[3]},
+			{det.Box[0], det.Box[3]},
+		}
+
+		minX, minY := float32(math.Inf(1)), float32(math.Inf(1))
+		maxX, maxY := float32(math.Inf(-1)), float32(math.Inf(-1))
+		valid := true
+		for _, c := range corners {
+			x, y, ok := applyHomography(homography, c[0], c[1])
+			if !ok {
+				valid = false
+				break
+			}
+			minX, maxX = minFloat32(minX, x), max(maxX, x)
+			minY, maxY = minFloat32(minY, y), max(maxY, y)
+		}
+		if !valid {
+			continue
+		}
+
+		det.Box = [4]float32{minX, minY, maxX, maxY}
+		result = append(result, det)
+	}
+	return result
+}
+
+// MergeCameraViews 将多个相机视角的检测结果变换到公共坐标系，并用MergeDetections去除重叠区域的重复目标
+func MergeCameraViews(iouThreshold float32, views ...CameraView) []Detection {
+	sets := make([][]Detection, 0, len(views))
+	for _, view := range views {
+		sets = append(sets, TransformDetections(view.Detections, view.Homography))
+	}
+	return MergeDetections(iouThreshold, sets...)
+}
+
+// applyHomography 对点 (x, y) 应用单应变换，齐次坐标w接近0（映射到无穷远）时返回false
+func applyHomography(h [9]float32, x, y float32) (float32, float32, bool) {
+	w := h[6]*x + h[7]*y + h[8]
+	if math.Abs(float64(w)) < 1e-9 {
+		return 0, 0, false
+	}
+	return (h[0]*x + h[1]*y + h[2]) / w, (h[3]*x + h[4]*y + h[5]) / w, true
+}
@@ -0,0 +1,156 @@
+package yolo
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	vidio "github.com/AlexEidt/Vidio"
+)
+
+// supportedHWAccels FFmpeg支持的硬件解码方式
+var supportedHWAccels = map[string]bool{
+	"auto":         true,
+	"cuda":         true,
+	"qsv":          true,
+	"videotoolbox": true,
+	"vaapi":        true,
+	"d3d11va":      true,
+	"dxva2":        true,
+}
+
+// videoSource 视频帧来源（vidio.Video 和硬件解码视频均实现该接口）
+type videoSource interface {
+	Read() bool
+	FrameBuffer() []byte
+	Width() int
+	Height() int
+	FPS() float64
+	Frames() int
+	Duration() float64
+	Close()
+}
+
+// openVideo 打开视频文件，配置了HWAccel时使用FFmpeg硬件解码读取帧
+func (y *YOLO) openVideo(path string) (videoSource, error) {
+	video, err := vidio.NewVideo(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if y == nil || y.config == nil || y.config.HWAccel == "" {
+		return video, nil
+	}
+	return &hwAccelVideo{Video: video, path: path, hwaccel: y.config.HWAccel}, nil
+}
+
+// hwAccelVideo 使用 ffmpeg -hwaccel 解码的视频，元数据仍由vidio探测
+// 硬件解码启动失败（驱动不可用等）且尚未读取任何帧时自动回退到软件解码
+type hwAccelVideo struct {
+	*vidio.Video
+	path        string
+	hwaccel     string
+	cmd         *exec.Cmd
+	pipe        io.ReadCloser
+	framebuffer []byte
+	frames      int
+	fallback    bool
+}
+
+// Read 读取下一帧，读取完毕返回false
+func (v *hwAccelVideo) Read() bool {
+	if v.fallback {
+		return v.Video.Read()
+	}
+
+	if v.cmd == nil {
+		if err := v.start(); err != nil {
+			return v.fallbackToSoftware(err)
+		}
+	}
+
+	if _, err := io.ReadFull(v.pipe, v.framebuffer); err != nil {
+		if v.frames == 0 {
+			return v.fallbackToSoftware(err)
+		}
+		v.stop()
+		return false
+	}
+	v.frames++
+	return true
+}
+
+// FrameBuffer 返回当前帧的RGBA数据
+func (v *hwAccelVideo) FrameBuffer() []byte {
+	if v.fallback {
+		return v.Video.FrameBuffer()
+	}
+	return v.framebuffer
+}
+
+// Close 停止解码进程
+func (v *hwAccelVideo) Close() {
+	v.stop()
+	v.Video.Close()
+}
+
+// start 启动FFmpeg硬件解码进程，输出RGBA原始帧
+func (v *hwAccelVideo) start() error {
+	cmd := exec.Command(
+		"ffmpeg",
+		"-hwaccel", v.hwaccel,
+		"-i", v.path,
+		"-f", "image2pipe",
+		"-loglevel", "quiet",
+		"-pix_fmt", "rgba",
+		"-vcodec", "rawvideo",
+		"-map", "0:v:0",
+		"-",
+	)
+
+	pipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	v.cmd = cmd
+	v.pipe = pipe
+	v.framebuffer = make([]byte, v.Width()*v.Height()*4)
+	fmt.Printf("⚡ 使用硬件解码 (%s): %s\n", v.hwaccel, v.path)
+	return nil
+}
+
+// stop 结束解码进程
+func (v *hwAccelVideo) stop() {
+	if v.cmd == nil {
+		return
+	}
+	if v.pipe != nil {
+		v.pipe.Close()
+	}
+	if v.cmd.Process != nil {
+		v.cmd.Process.Kill()
+	}
+	v.cmd.Wait()
+	v.cmd = nil
+}
+
+// fallbackToSoftware 硬件解码不可用时回退到vidio软件解码并读取第一帧
+func (v *hwAccelVideo) fallbackToSoftware(err error) bool {
+	fmt.Printf("⚠️  硬件解码 (%s) 不可用，回退到软件解码: %v\n", v.hwaccel, err)
+	v.stop()
+	v.fallback = true
+	return v.Video.Read()
+}
+
+// validateHWAccel 检查硬件解码方式是否受支持
+func validateHWAccel(kind string) error {
+	if kind == "" || supportedHWAccels[strings.ToLower(kind)] {
+		return nil
+	}
+	return fmt.Errorf("不支持的硬件解码方式: %s（可选: cuda, qsv, videotoolbox, vaapi, d3d11va, dxva2, auto）", kind)
+}
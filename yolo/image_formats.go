@@ -0,0 +1,30 @@
+package yolo
+
+import (
+	"path/filepath"
+	"strings"
+
+	// 注册额外的图像解码器，image.Decode 和 imaging.Open 可直接读取这些格式
+	_ "image/gif"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// imageExts 支持检测的图像文件扩展名
+var imageExts = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".bmp":  true,
+	".gif":  true,
+	".tif":  true,
+	".tiff": true,
+	".webp": true,
+}
+
+// isImageFile 检查是否为支持的图像文件
+func isImageFile(path string) bool {
+	return imageExts[strings.ToLower(filepath.Ext(path))]
+}
@@ -0,0 +1,72 @@
+package yolo
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// runSession 运行一次推理，设置了InferenceTimeout时最多等待该时长
+// 超时返回ErrInferenceTimeout：卡住的推理仍在后台使用输入输出张量，
+// 由后台goroutine在推理真正结束后释放，调用方不能再释放这些张量
+// 同一时间最多保留一个超时未结束的推理，在它结束前的推理直接返回ErrInferenceTimeout（张量同样由这里释放），
+// 避免会话卡死时每帧都堆积一个goroutine和一组张量；Close会等待这个推理结束后才销毁会话
+func (y *YOLO) runSession(inputs, outputs []ort.Value) error {
+	if y.inferenceHung() {
+		destroyValues(inputs)
+		destroyValues(outputs)
+		return fmt.Errorf("%w（上一次超时的推理仍未结束）", ErrInferenceTimeout)
+	}
+
+	atomic.StoreInt64(&y.lastInference, time.Now().UnixNano()) // 空闲保温据此判断检测器是否空闲
+
+	timeout := time.Duration(0)
+	if y.runtimeConfig != nil {
+		timeout = y.runtimeConfig.InferenceTimeout
+	}
+	if timeout <= 0 {
+		return y.session.Run(inputs, outputs)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- y.session.Run(inputs, outputs)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		fmt.Printf("⏰ 推理超过 %v 未返回，跳过本帧\n", timeout)
+		atomic.StoreInt32(&y.abandonedRun, 1)
+		y.abandonedWG.Add(1)
+		go func() {
+			defer y.abandonedWG.Done()
+			<-done
+			destroyValues(inputs)
+			destroyValues(outputs)
+			atomic.StoreInt32(&y.abandonedRun, 0)
+			fmt.Println("✅ 超时的推理已结束，恢复检测")
+		}()
+		return fmt.Errorf("%w（%v）", ErrInferenceTimeout, timeout)
+	}
+}
+
+// inferenceHung 是否有超时后仍未结束的推理（此时会话不可用）
+func (y *YOLO) inferenceHung() bool {
+	return atomic.LoadInt32(&y.abandonedRun) != 0
+}
+
+// destroyValues 释放非空的张量
+func destroyValues(values []ort.Value) {
+	for _, v := range values {
+		if v != nil {
+			v.Destroy()
+		}
+	}
+}
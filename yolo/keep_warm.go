@@ -0,0 +1,90 @@
+package yolo
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// keepWarmLoop 空闲保温任务：检测器空闲超过间隔时运行一次丢弃结果的推理，避免GPU空闲降频后首次推理变慢
+type keepWarmLoop struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// startKeepWarm 启动空闲保温任务（interval<=0时不启动）
+func (y *YOLO) startKeepWarm(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	loop := &keepWarmLoop{stop: make(chan struct{}), done: make(chan struct{})}
+	y.keepWarm = loop
+	atomic.StoreInt64(&y.lastInference, time.Now().UnixNano())
+
+	go func() {
+		defer close(loop.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-loop.stop:
+				return
+			case <-ticker.C:
+				idle := time.Since(time.Unix(0, atomic.LoadInt64(&y.lastInference)))
+				if idle < interval || y.inferenceHung() {
+					continue
+				}
+				if err := y.warmInference(); err != nil {
+					fmt.Printf("⚠️  保温推理失败: %v\n", err)
+					continue
+				}
+				atomic.StoreInt64(&y.lastInference, time.Now().UnixNano())
+			}
+		}
+	}()
+
+	fmt.Printf("🔥 已启用空闲保温推理（间隔 %v）\n", interval)
+}
+
+// stopKeepWarm 停止空闲保温任务并等待正在运行的保温推理结束
+func (y *YOLO) stopKeepWarm() {
+	if y.keepWarm == nil {
+		return
+	}
+	close(y.keepWarm.stop)
+	<-y.keepWarm.done
+	y.keepWarm = nil
+}
+
+// warmInference 使用合成输入运行一次推理并丢弃结果，经runSession运行以受InferenceTimeout约束
+func (y *YOLO) warmInference() error {
+	width, height := y.config.InputSize, y.config.InputSize
+	if y.config.InputWidth > 0 && y.config.InputHeight > 0 {
+		width, height = y.config.InputWidth, y.config.InputHeight
+	}
+
+	inputTensor, err := ort.NewTensor(ort.NewShape(1, 3, int64(height), int64(width)), make([]float32, 3*width*height))
+	if err != nil {
+		return fmt.Errorf("无法创建输入张量: %v", err)
+	}
+
+	// 输出由ONNX Runtime按实际形状分配（锚框模型每个输出头一个输出）
+	outputCount := 1
+	if y.config.Anchors != nil {
+		outputCount = len(y.config.Anchors.Strides)
+	}
+	outputs := make([]ort.Value, outputCount)
+	err = y.runSession([]ort.Value{inputTensor}, outputs)
+	if errors.Is(err, ErrInferenceTimeout) {
+		return err // 张量由runSession在超时的推理结束后释放
+	}
+	inputTensor.Destroy()
+	destroyValues(outputs)
+	return err
+}
@@ -0,0 +1,92 @@
+package yolo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// defaultScoreFormat 默认置信度显示格式（两位小数）
+const defaultScoreFormat = ".2f"
+
+// formatLabel 生成检测结果的标签文本
+// 未设置LabelTemplate时为 "类别 分数"，有跟踪ID时在前面加上 "#ID"
+func (y *YOLO) formatLabel(d Detection) string {
+	scoreFormat := defaultScoreFormat
+	template := ""
+	if y.runtimeConfig != nil {
+		if y.runtimeConfig.ScoreFormat != "" {
+			scoreFormat = y.runtimeConfig.ScoreFormat
+		}
+		template = y.runtimeConfig.LabelTemplate
+	}
+
+	if template == "" {
+		label := d.Class + " " + formatScore(d.Score, scoreFormat)
+		if d.TrackID > 0 {
+			label = fmt.Sprintf("#%d %s", d.TrackID, label)
+		}
+		return label
+	}
+	return renderLabelTemplate(template, d, scoreFormat)
+}
+
+// renderLabelTemplate 渲染标签模板
+// 支持的占位符: {class} {class_id} {score} {id}（跟踪ID） {area}（像素面积），
+// score可带格式如 {score:.0%}、{score:.3f}；未知占位符原样保留
+func renderLabelTemplate(template string, d Detection, scoreFormat string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(template[:start])
+		token := template[start+1 : end]
+		name, spec, _ := strings.Cut(token, ":")
+
+		switch name {
+		case "class":
+			b.WriteString(d.Class)
+		case "class_id":
+			b.WriteString(strconv.Itoa(d.ClassID))
+		case "score":
+			if spec == "" {
+				spec = scoreFormat
+			}
+			b.WriteString(formatScore(d.Score, spec))
+		case "id":
+			if d.TrackID > 0 {
+				b.WriteString(strconv.Itoa(d.TrackID))
+			}
+		case "area":
+			b.WriteString(strconv.Itoa(int(boxArea(d.Box))))
+		default:
+			b.WriteString(template[start : end+1])
+		}
+		template = template[end+1:]
+	}
+	b.WriteString(template)
+	return b.String()
+}
+
+// formatScore 按格式显示置信度：".Nf" 为N位小数，".N%" 为N位小数的百分比
+func formatScore(score float32, spec string) string {
+	percent := strings.HasSuffix(spec, "%")
+	digits := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(spec, "."), "%"), "f")
+	decimals, err := strconv.Atoi(digits)
+	if err != nil || decimals < 0 {
+		decimals = 2
+	}
+
+	if percent {
+		return strconv.FormatFloat(float64(score)*100, 'f', decimals, 32) + "%"
+	}
+	return strconv.FormatFloat(float64(score), 'f', decimals, 32)
+}
@@ -0,0 +1,97 @@
+package yolo
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sort"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// classColor 返回类别对应的检测框颜色（ClassColors中未配置时使用默认框颜色）
+func (y *YOLO) classColor(class string, defaultColor color.RGBA) color.RGBA {
+	if y.runtimeConfig != nil && y.runtimeConfig.ClassColors != nil {
+		if colorStr, ok := y.runtimeConfig.ClassColors[class]; ok {
+			if parsedColor := y.parseColor(colorStr); parsedColor != nil {
+				return *parsedColor
+			}
+		}
+	}
+	return defaultColor
+}
+
+// drawLegend 在图像右上角绘制图例：每个类别的颜色块、类别名和当前帧数量
+func (y *YOLO) drawLegend(img *image.RGBA, detections []Detection, defaultColor color.RGBA) {
+	if len(detections) == 0 {
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, det := range detections {
+		counts[det.Class]++
+	}
+	classes := make([]string, 0, len(counts))
+	for class := range counts {
+		classes = append(classes, class)
+	}
+	// 数量多的类别排在前面
+	sort.Slice(classes, func(i, j int) bool {
+		if counts[classes[i]] != counts[classes[j]] {
+			return counts[classes[i]] > counts[classes[j]]
+		}
+		return classes[i] < classes[j]
+	})
+
+	const (
+		lineHeight = 16
+		swatchSize = 10
+		padding    = 6
+		charWidth  = 7 // basicfont.Face7x13
+	)
+
+	labels := make([]string, len(classes))
+	textWidth := 0
+	for i, class := range classes {
+		labels[i] = fmt.Sprintf("%s (%d)", class, counts[class])
+		if w := len(labels[i]) * charWidth; w > textWidth {
+			textWidth = w
+		}
+	}
+
+	bounds := img.Bounds()
+	width := padding*3 + swatchSize + textWidth
+	height := padding*2 + lineHeight*len(labels)
+	origin := image.Pt(bounds.Max.X-width-padding, bounds.Min.Y+padding)
+	if origin.X < bounds.Min.X {
+		origin.X = bounds.Min.X
+	}
+
+	// 半透明黑色背景
+	background := image.Rect(origin.X, origin.Y, origin.X+width, origin.Y+height).Intersect(bounds)
+	draw.Draw(img, background, image.NewUniform(color.RGBA{0, 0, 0, 160}), image.Point{}, draw.Over)
+
+	textColor := color.RGBA{255, 255, 255, 255}
+	if y.runtimeConfig != nil {
+		if parsedColor := y.parseColor(y.runtimeConfig.LabelColor); parsedColor != nil {
+			textColor = *parsedColor
+		}
+	}
+
+	for i, class := range classes {
+		top := origin.Y + padding + i*lineHeight
+		swatch := image.Rect(origin.X+padding, top+3, origin.X+padding+swatchSize, top+3+swatchSize)
+		draw.Draw(img, swatch.Intersect(bounds), image.NewUniform(y.classColor(class, defaultColor)), image.Point{}, draw.Src)
+
+		d := &font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(textColor),
+			Face: basicfont.Face7x13,
+			Dot:  fixed.P(swatch.Max.X+padding, top+lineHeight-3),
+		}
+		d.DrawString(labels[i])
+	}
+}
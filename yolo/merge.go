@@ -0,0 +1,102 @@
+package yolo
+
+import "sort"
+
+// MergeDetections 合并多组检测结果并按类别执行NMS去除重复框
+// 适用于分块检测、多模型集成、全景拼接等场景；不同类别的框互不抑制
+func MergeDetections(iouThreshold float32, sets ...[]Detection) []Detection {
+	total := 0
+	for _, set := range sets {
+		total += len(set)
+	}
+	if total == 0 {
+		return nil
+	}
+
+	merged := make([]Detection, 0, total)
+	for _, set := range sets {
+		merged = append(merged, set...)
+	}
+
+	return classAwareNMS(merged, iouThreshold)
+}
+
+// classAwareNMS 按类别执行非极大抑制（结果按分数从高到低排序）
+func classAwareNMS(detections []Detection, iouThreshold float32) []Detection {
+	sort.SliceStable(detections, func(i, j int) bool {
+		return detections[i].Score > detections[j].Score
+	})
+
+	var keep []Detection
+	for _, current := range detections {
+		keepCurrent := true
+		for _, kept := range keep {
+			if kept.Class == current.Class && kept.ClassID == current.ClassID && boxIOU(current.Box, kept.Box) > iouThreshold {
+				keepCurrent = false
+				break
+			}
+		}
+		if keepCurrent {
+			keep = append(keep, current)
+		}
+	}
+
+	return keep
+}
+
+// weightedBoxesFusion 加权框融合（WBF）：按类别聚类重叠框，用分数加权平均坐标代替只保留最高分框
+// 融合后的分数为簇内平均分，结果按分数从高到低排序
+func weightedBoxesFusion(detections []Detection, iouThreshold float32) []Detection {
+	if len(detections) == 0 {
+		return detections
+	}
+
+	sort.SliceStable(detections, func(i, j int) bool {
+		return detections[i].Score > detections[j].Score
+	})
+
+	type cluster struct {
+		fused      Detection
+		weightedXY [4]float32
+		scoreSum   float32
+		count      int
+	}
+
+	var clusters []*cluster
+	for _, det := range detections {
+		var target *cluster
+		for _, c := range clusters {
+			if c.fused.ClassID == det.ClassID && c.fused.Class == det.Class && boxIOU(c.fused.Box, det.Box) > iouThreshold {
+				target = c
+				break
+			}
+		}
+		if target == nil {
+			target = &cluster{fused: det}
+			clusters = append(clusters, target)
+		}
+
+		for k := 0; k < 4; k++ {
+			target.weightedXY[k] += det.Box[k] * det.Score
+		}
+		target.scoreSum += det.Score
+		target.count++
+
+		// 更新融合框，后续框与融合后的框比较IOU
+		if target.scoreSum > 0 {
+			for k := 0; k < 4; k++ {
+				target.fused.Box[k] = target.weightedXY[k] / target.scoreSum
+			}
+		}
+		target.fused.Score = target.scoreSum / float32(target.count)
+	}
+
+	fused := make([]Detection, 0, len(clusters))
+	for _, c := range clusters {
+		fused = append(fused, c.fused)
+	}
+	sort.SliceStable(fused, func(i, j int) bool {
+		return fused[i].Score > fused[j].Score
+	})
+	return fused
+}
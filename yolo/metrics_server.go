@@ -0,0 +1,107 @@
+package yolo
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// metricsContentType Prometheus文本格式
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WriteMetrics 以Prometheus文本格式输出优化管线的性能指标
+// 包括请求计数、延迟、吞吐量、熔断器状态和队列深度
+func (vo *VideoOptimization) WriteMetrics(w io.Writer) {
+	writeMetric := func(name, metricType, help string, value interface{}) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, metricType, name, value)
+	}
+
+	vo.metrics.mu.RLock()
+	totalRequests := vo.metrics.totalRequests
+	successRequests := vo.metrics.successRequests
+	failedRequests := vo.metrics.failedRequests
+	avgLatency := vo.metrics.avgLatency
+	maxLatency := vo.metrics.maxLatency
+	minLatency := vo.metrics.minLatency
+	throughput := vo.metrics.throughput
+	vo.metrics.mu.RUnlock()
+
+	writeMetric("yolo_requests_total", "counter", "Total detection requests.", totalRequests)
+	writeMetric("yolo_requests_success_total", "counter", "Successful detection requests.", successRequests)
+	writeMetric("yolo_requests_failed_total", "counter", "Failed detection requests.", failedRequests)
+
+	fmt.Fprintf(w, "# HELP yolo_latency_seconds Detection latency.\n# TYPE yolo_latency_seconds gauge\n")
+	fmt.Fprintf(w, "yolo_latency_seconds{stat=\"avg\"} %v\n", avgLatency.Seconds())
+	fmt.Fprintf(w, "yolo_latency_seconds{stat=\"max\"} %v\n", maxLatency.Seconds())
+	fmt.Fprintf(w, "yolo_latency_seconds{stat=\"min\"} %v\n", minLatency.Seconds())
+
+	writeMetric("yolo_throughput_fps", "gauge", "Detection throughput in frames per second.", throughput)
+
+	vo.circuitBreaker.mu.RLock()
+	state := vo.circuitBreaker.state
+	failureCount := vo.circuitBreaker.failureCount
+	vo.circuitBreaker.mu.RUnlock()
+
+	writeMetric("yolo_circuit_breaker_state", "gauge", "Circuit breaker state (0=closed, 1=open, 2=half-open).", int(state))
+	writeMetric("yolo_circuit_breaker_failures", "gauge", "Consecutive failures seen by the circuit breaker.", failureCount)
+
+	asyncQueueLen, processDoneLen, availableWorkers := vo.GetQueueStatus()
+	writeMetric("yolo_async_queue_length", "gauge", "Pending tasks in the async queue.", asyncQueueLen)
+	writeMetric("yolo_result_queue_length", "gauge", "Results waiting to be collected.", processDoneLen)
+	writeMetric("yolo_available_workers", "gauge", "Idle workers in the worker pool.", availableWorkers)
+	writeMetric("yolo_dropped_results_total", "counter", "Results dropped because the result queue was full.", vo.DroppedResults())
+
+	vo.resourceMonitor.mu.RLock()
+	memoryUsage := vo.resourceMonitor.memoryUsage
+	goroutineCount := vo.resourceMonitor.goroutineCount
+	vo.resourceMonitor.mu.RUnlock()
+
+	writeMetric("yolo_memory_usage_bytes", "gauge", "Heap memory in use.", memoryUsage)
+	writeMetric("yolo_goroutines", "gauge", "Number of goroutines.", goroutineCount)
+
+	healthy := 0
+	if vo.IsHealthy() {
+		healthy = 1
+	}
+	writeMetric("yolo_healthy", "gauge", "Whether the optimization pipeline is healthy.", healthy)
+}
+
+// MetricsHandler 返回输出Prometheus指标的HTTP处理器
+func (vo *VideoOptimization) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", metricsContentType)
+		vo.WriteMetrics(w)
+	})
+}
+
+// StartMetricsServer 在指定地址启动指标服务，Prometheus可抓取 /metrics
+// 返回的http.Server可用于Shutdown关闭服务
+func (vo *VideoOptimization) StartMetricsServer(addr string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("启动指标服务失败: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", vo.MetricsHandler())
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("❌ 指标服务异常退出: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("📈 指标服务已启动: http://%s/metrics\n", listener.Addr())
+	return server, nil
+}
+
+// StartMetricsServer 为检测器共享的优化实例启动指标服务
+func (y *YOLO) StartMetricsServer(addr string) (*http.Server, error) {
+	return y.GetVideoOptimization().StartMetricsServer(addr)
+}
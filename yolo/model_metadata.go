@@ -0,0 +1,66 @@
+package yolo
+
+import (
+	"fmt"
+	"sort"
+
+	ort "github.com/yalue/onnxruntime_go"
+	"gopkg.in/yaml.v3"
+)
+
+// loadClassesFromModelMetadata 从ONNX模型元数据的names字段加载类别列表（Ultralytics导出时写入）
+func loadClassesFromModelMetadata(modelPath string) error {
+	metadata, err := ort.GetModelMetadata(modelPath)
+	if err != nil {
+		return fmt.Errorf("读取模型元数据失败: %v", err)
+	}
+	defer metadata.Destroy()
+
+	value, ok, err := metadata.LookupCustomMetadataMap("names")
+	if err != nil {
+		return fmt.Errorf("读取模型元数据失败: %v", err)
+	}
+	if !ok || value == "" {
+		return fmt.Errorf("模型元数据中没有names字段")
+	}
+
+	classes, err := parseMetadataNames(value)
+	if err != nil {
+		return err
+	}
+
+	SetClasses(classes)
+	fmt.Printf("✅ 从模型元数据加载 %d 个类别\n", len(classes))
+	return nil
+}
+
+// parseMetadataNames 解析Ultralytics的names元数据，格式为Python字典 {0: 'person', 1: 'bicycle', ...}
+// 该格式同时是合法的YAML流式映射；缺失的索引使用 class_<索引> 占位
+func parseMetadataNames(value string) ([]string, error) {
+	var names map[int]string
+	if err := yaml.Unmarshal([]byte(value), &names); err != nil {
+		return nil, fmt.Errorf("解析模型元数据中的类别失败: %v", err)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("模型元数据中的类别列表为空")
+	}
+
+	ids := make([]int, 0, len(names))
+	for id := range names {
+		if id < 0 {
+			return nil, fmt.Errorf("模型元数据中的类别索引无效: %d", id)
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	classes := make([]string, ids[len(ids)-1]+1)
+	for i := range classes {
+		if name, ok := names[i]; ok {
+			classes[i] = name
+		} else {
+			classes[i] = fmt.Sprintf("class_%d", i)
+		}
+	}
+	return classes, nil
+}
@@ -0,0 +1,81 @@
+package yolo
+
+import (
+	"image"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// motionThumbWidth 运动检测缩略图宽度（高度按比例），在小图上比较可忽略噪点且开销极低
+	motionThumbWidth = 64
+	// motionMaxSkippedFrames 连续跳过检测的最大帧数，超过后强制检测一次避免结果长期不更新
+	motionMaxSkippedFrames = 30
+)
+
+// motionGate 帧差运动门控状态
+type motionGate struct {
+	prev       []uint8 // 上一次检测帧的灰度缩略图
+	bounds     image.Rectangle
+	detections []Detection // 上一次检测的结果
+	skipped    int
+}
+
+// motionGated 判断当前帧相对上一次检测帧是否基本静止，静止时返回可复用的上次检测结果
+func (y *YOLO) motionGated(img image.Image) ([]Detection, bool) {
+	if y.runtimeConfig == nil || y.runtimeConfig.MotionThreshold <= 0 {
+		return nil, false
+	}
+
+	gate := &y.motion
+	if gate.prev == nil || gate.bounds != img.Bounds() || gate.skipped >= motionMaxSkippedFrames {
+		return nil, false
+	}
+
+	if frameDifference(gate.prev, motionThumbnail(img)) >= y.runtimeConfig.MotionThreshold {
+		return nil, false
+	}
+
+	gate.skipped++
+	return append([]Detection(nil), gate.detections...), true
+}
+
+// recordMotionFrame 记录实际检测过的帧及其结果，作为后续帧差比较的基准
+func (y *YOLO) recordMotionFrame(img image.Image, detections []Detection) {
+	if y.runtimeConfig == nil || y.runtimeConfig.MotionThreshold <= 0 {
+		return
+	}
+
+	y.motion = motionGate{
+		prev:       motionThumbnail(img),
+		bounds:     img.Bounds(),
+		detections: append([]Detection(nil), detections...),
+	}
+}
+
+// motionThumbnail 生成用于帧差比较的灰度缩略图
+func motionThumbnail(img image.Image) []uint8 {
+	thumb := imaging.Grayscale(imaging.Resize(img, motionThumbWidth, 0, imaging.Box))
+	pixels := make([]uint8, 0, len(thumb.Pix)/4)
+	for i := 0; i < len(thumb.Pix); i += 4 {
+		pixels = append(pixels, thumb.Pix[i])
+	}
+	return pixels
+}
+
+// frameDifference 计算两张灰度缩略图的平均绝对差（0-1）
+func frameDifference(a, b []uint8) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 1
+	}
+
+	var sum int
+	for i := range a {
+		d := int(a[i]) - int(b[i])
+		if d < 0 {
+			d = -d
+		}
+		sum += d
+	}
+	return float64(sum) / float64(len(a)*255)
+}
@@ -0,0 +1,231 @@
+package yolo
+
+import (
+	"fmt"
+	"image"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MultiGPUDetector 多GPU检测器
+// 在每个GPU上各创建一个会话，按轮询方式分发图片和视频帧，吞吐量随GPU数量扩展
+type MultiGPUDetector struct {
+	detectors []*YOLO
+	locks     []sync.Mutex // 每个会话同一时间只处理一个请求
+	next      uint64       // 轮询计数
+}
+
+// NewYOLOMultiGPU 在deviceIDs指定的每个GPU上加载模型，返回负载均衡的检测器
+// config可选，作为所有设备的基础配置（UseGPU和GPUDeviceID会被覆盖）
+func NewYOLOMultiGPU(modelPath, configPath string, deviceIDs []int, config ...*YOLOConfig) (*MultiGPUDetector, error) {
+	if len(deviceIDs) == 0 {
+		return nil, fmt.Errorf("至少需要指定一个GPU设备ID")
+	}
+
+	base := DefaultConfig()
+	if len(config) > 0 && config[0] != nil {
+		base = config[0]
+	}
+
+	seen := make(map[int]bool, len(deviceIDs))
+	m := &MultiGPUDetector{locks: make([]sync.Mutex, len(deviceIDs))}
+	for _, id := range deviceIDs {
+		if seen[id] {
+			m.Close()
+			return nil, fmt.Errorf("GPU设备ID重复: %d", id)
+		}
+		seen[id] = true
+
+		cfg := *base
+		cfg.UseGPU = true
+		cfg.GPUDeviceID = id
+
+		fmt.Printf("🚀 在GPU %d 上加载模型...\n", id)
+		detector, err := NewYOLO(modelPath, configPath, &cfg)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("GPU %d 创建检测器失败: %v", id, err)
+		}
+		m.detectors = append(m.detectors, detector)
+	}
+
+	fmt.Printf("✅ 多GPU检测器已就绪: %d 个设备 %v\n", len(deviceIDs), deviceIDs)
+	return m, nil
+}
+
+// Detectors 返回各GPU上的检测器（按deviceIDs顺序）
+func (m *MultiGPUDetector) Detectors() []*YOLO {
+	return m.detectors
+}
+
+// SetRuntimeConfig 为所有GPU上的检测器设置检测选项
+func (m *MultiGPUDetector) SetRuntimeConfig(options *DetectionOptions) {
+	for i, detector := range m.detectors {
+		m.locks[i].Lock()
+		detector.SetRuntimeConfig(options)
+		m.locks[i].Unlock()
+	}
+}
+
+// DetectImage 将图片分发到下一个GPU检测
+func (m *MultiGPUDetector) DetectImage(imagePath string) ([]Detection, error) {
+	var detections []Detection
+	err := m.withNext(func(detector *YOLO) error {
+		var err error
+		detections, err = detector.DetectImage(imagePath)
+		return err
+	})
+	return detections, err
+}
+
+// DetectImageImage 将内存中的图像分发到下一个GPU检测
+func (m *MultiGPUDetector) DetectImageImage(img image.Image) ([]Detection, error) {
+	var detections []Detection
+	err := m.withNext(func(detector *YOLO) error {
+		var err error
+		detections, err = detector.DetectImageImage(img)
+		return err
+	})
+	return detections, err
+}
+
+// DetectImages 并行检测多张图片，第i张图片分配给第 i%设备数 个GPU，结果按输入顺序返回
+func (m *MultiGPUDetector) DetectImages(imagePaths []string) ([][]Detection, error) {
+	results := make([][]Detection, len(imagePaths))
+	err := m.dispatch(len(imagePaths), func(detector *YOLO, i int) error {
+		detections, err := detector.DetectImage(imagePaths[i])
+		if err != nil {
+			return fmt.Errorf("图片 %s 检测失败: %v", imagePaths[i], err)
+		}
+		results[i] = detections
+		return nil
+	})
+	return results, err
+}
+
+// DetectFrames 并行检测多帧图像，分配方式与DetectImages相同
+func (m *MultiGPUDetector) DetectFrames(frames []image.Image) ([][]Detection, error) {
+	results := make([][]Detection, len(frames))
+	err := m.dispatch(len(frames), func(detector *YOLO, i int) error {
+		detections, err := detector.DetectImageImage(frames[i])
+		if err != nil {
+			return fmt.Errorf("第 %d 帧检测失败: %v", i, err)
+		}
+		results[i] = detections
+		return nil
+	})
+	return results, err
+}
+
+// DetectVideo 检测视频文件，帧按轮询方式分发到各GPU，结果按帧号顺序返回
+func (m *MultiGPUDetector) DetectVideo(inputPath string) ([]VideoDetectionResult, error) {
+	if !isVideoFile(inputPath) {
+		return nil, fmt.Errorf("不支持的文件格式，请使用MP4等视频文件")
+	}
+
+	video, err := m.detectors[0].openVideo(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("无法打开视频文件: %v", err)
+	}
+	defer video.Close()
+
+	fmt.Printf("📹 视频信息: %dx%d, %.2f FPS, %d 帧, %.2f 秒, %d 个GPU\n",
+		video.Width(), video.Height(), video.FPS(), video.Frames(), video.Duration(), len(m.detectors))
+	clock := m.detectors[0].newFrameClock(inputPath, video.FPS())
+
+	// 每个GPU一个工作协程，帧按读取顺序轮询分配
+	var results []VideoDetectionResult
+	var resultsMu sync.Mutex
+	var wg sync.WaitGroup
+	queues := make([]chan VideoDetectionResult, len(m.detectors))
+	for i := range m.detectors {
+		queues[i] = make(chan VideoDetectionResult, 2)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for frame := range queues[i] {
+				m.locks[i].Lock()
+				start := time.Now()
+				detections, err := m.detectors[i].detectImage(frame.Image)
+				frame.ProcessingTime = time.Since(start)
+				m.locks[i].Unlock()
+				if err != nil {
+					fmt.Printf("⚠️  帧 %d 检测失败: %v\n", frame.FrameNumber, err)
+					detections = []Detection{}
+				}
+				frame.Detections = detections
+
+				resultsMu.Lock()
+				results = append(results, frame)
+				resultsMu.Unlock()
+			}
+		}(i)
+	}
+
+	frameCount := 0
+	for video.Read() {
+		frameCount++
+		queues[(frameCount-1)%len(queues)] <- VideoDetectionResult{
+			FrameNumber: frameCount,
+			Timestamp:   clock.At(frameCount),
+			Image:       convertFrameBufferToImage(video.FrameBuffer(), video.Width(), video.Height()),
+		}
+
+		if frameCount%30 == 0 || frameCount == video.Frames() {
+			m.detectors[0].verbosef("📊 已分发 %d/%d 帧...\n", frameCount, video.Frames())
+		}
+	}
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].FrameNumber < results[j].FrameNumber })
+	fmt.Printf("✅ 视频处理完成！共处理 %d 帧\n", frameCount)
+	return results, nil
+}
+
+// Close 关闭所有GPU上的检测器
+func (m *MultiGPUDetector) Close() {
+	for _, detector := range m.detectors {
+		detector.Close()
+	}
+}
+
+// withNext 在轮询选中的检测器上执行fn
+func (m *MultiGPUDetector) withNext(fn func(detector *YOLO) error) error {
+	i := int((atomic.AddUint64(&m.next, 1) - 1) % uint64(len(m.detectors)))
+	m.locks[i].Lock()
+	defer m.locks[i].Unlock()
+	return fn(m.detectors[i])
+}
+
+// dispatch 按 i%设备数 将n个任务分配给各GPU并行执行，返回第一个错误
+func (m *MultiGPUDetector) dispatch(n int, fn func(detector *YOLO, i int) error) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(m.detectors))
+	for d := range m.detectors {
+		wg.Add(1)
+		go func(d int) {
+			defer wg.Done()
+			m.locks[d].Lock()
+			defer m.locks[d].Unlock()
+			for i := d; i < n; i += len(m.detectors) {
+				if err := fn(m.detectors[d], i); err != nil {
+					errs[d] = err
+					return
+				}
+			}
+		}(d)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
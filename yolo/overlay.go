@@ -0,0 +1,88 @@
+package yolo
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// annotateFrame 绘制视频帧的检测结果和帧级叠加信息（时间戳、FPS等）
+// timestamp 为帧在视频中的时间，<0 表示使用当前系统时间（实时流）
+func (y *YOLO) annotateFrame(frame image.Image, detections []Detection, timestamp time.Duration) image.Image {
+	var result image.Image = frame
+	if len(detections) > 0 {
+		result = y.drawDetectionsOnImage(frame, detections)
+	}
+
+	if y.runtimeConfig == nil || (!y.runtimeConfig.TimestampOverlay && !y.runtimeConfig.ShowFPS) {
+		return result
+	}
+
+	rgba, ok := result.(*image.RGBA)
+	if !ok || rgba == frame {
+		// 不修改调用方的原始帧
+		bounds := result.Bounds()
+		rgba = image.NewRGBA(bounds)
+		draw.Draw(rgba, bounds, result, bounds.Min, draw.Src)
+	}
+
+	if y.runtimeConfig.TimestampOverlay {
+		text := time.Now().Format("2006-01-02 15:04:05")
+		if timestamp >= 0 {
+			text = formatTimestamp(timestamp)
+		}
+		drawCornerText(rgba, text, true)
+	}
+
+	if y.runtimeConfig.ShowFPS {
+		y.fps.SetWindow(y.runtimeConfig.FPSWindow, y.runtimeConfig.FPSWarmupFrames)
+		text := "FPS: --" // 预热阶段不显示不准确的帧率
+		if fps := y.fps.Tick(); fps > 0 {
+			text = fmt.Sprintf("FPS: %.1f", fps)
+		}
+		drawCornerText(rgba, text, false)
+	}
+	return rgba
+}
+
+// formatTimestamp 将视频时间格式化为 HH:MM:SS.mmm
+func formatTimestamp(d time.Duration) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// drawCornerText 在图像左下角（bottom为false时左上角）绘制带半透明背景的文本
+func drawCornerText(img *image.RGBA, text string, bottom bool) {
+	const (
+		padding    = 4
+		charWidth  = 7 // basicfont.Face7x13
+		textHeight = 13
+	)
+
+	bounds := img.Bounds()
+	top := bounds.Min.Y + padding
+	if bottom {
+		top = bounds.Max.Y - textHeight - padding*3
+	}
+	box := image.Rect(
+		bounds.Min.X+padding,
+		top,
+		bounds.Min.X+padding*3+len(text)*charWidth,
+		top+textHeight+padding*2,
+	).Intersect(bounds)
+	draw.Draw(img, box, image.NewUniform(color.RGBA{0, 0, 0, 160}), image.Point{}, draw.Over)
+
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(color.RGBA{255, 255, 255, 255}),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(box.Min.X+padding, box.Max.Y-padding-2),
+	}
+	d.DrawString(text)
+}
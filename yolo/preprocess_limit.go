@@ -0,0 +1,39 @@
+package yolo
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/disintegration/imaging"
+)
+
+// limitDimension 最长边超过maxDimension时先用盒式滤波（区域平均）等比例缩小，maxDimension<=0表示不限制
+// 超大图像（如50MP扫描件）直接用Lanczos缩放到模型输入尺寸时，每个输出像素需要对数十个源像素加权，
+// 先快速缩小到中间尺寸可以大幅降低预处理耗时；模型输入和坐标转换仍基于原图尺寸，检测框坐标不受影响
+func limitDimension(img image.Image, maxDimension int) image.Image {
+	if maxDimension <= 0 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() <= maxDimension && bounds.Dy() <= maxDimension {
+		return img
+	}
+	if bounds.Dx() >= bounds.Dy() {
+		return imaging.Resize(img, maxDimension, 0, imaging.Box)
+	}
+	return imaging.Resize(img, 0, maxDimension, imaging.Box)
+}
+
+// resizeForInput 将图像缩放到模型输入尺寸（超过MaxPreprocessDimension时先缩小）
+func (y *YOLO) resizeForInput(img image.Image, width, height int) image.Image {
+	if limit := y.config.MaxPreprocessDimension; limit > 0 {
+		if bounds := img.Bounds(); bounds.Dx() > limit || bounds.Dy() > limit {
+			y.largeImageWarnOnce.Do(func() {
+				fmt.Printf("⚠️  输入图像 %dx%d 超过预处理尺寸上限 %d，已先缩小再送入模型（小目标可能丢失，可使用WithCropRegion检测局部区域）\n",
+					bounds.Dx(), bounds.Dy(), limit)
+			})
+		}
+	}
+	return imaging.Resize(limitDimension(img, y.config.MaxPreprocessDimension), width, height, y.config.ResizeFilter.resample())
+}
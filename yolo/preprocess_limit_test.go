@@ -0,0 +1,50 @@
+package yolo
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// syntheticImage 按坐标计算像素的大尺寸图像，不分配像素缓冲区
+type syntheticImage struct {
+	width, height int
+}
+
+func (s syntheticImage) ColorModel() color.Model { return color.RGBAModel }
+
+func (s syntheticImage) Bounds() image.Rectangle { return image.Rect(0, 0, s.width, s.height) }
+
+func (s syntheticImage) At(x, y int) color.Color {
+	return color.RGBA{R: uint8(x), G: uint8(y), B: uint8(x + y), A: 255}
+}
+
+// TestLimitDimensionKeepsAspectRatio 超大图像按最长边等比例缩小，未超过上限的图像原样返回
+func TestLimitDimensionKeepsAspectRatio(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		limit         int
+		wantW, wantH  int
+	}{
+		{"横向超大图像", 8000, 4500, 1280, 1280, 720},
+		{"纵向超大图像", 3000, 9000, 1920, 640, 1920},
+		{"未超过上限", 1280, 720, 1920, 1280, 720},
+		{"不限制", 8000, 4500, 0, 8000, 4500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := syntheticImage{tt.width, tt.height}
+			got := limitDimension(src, tt.limit).Bounds()
+			if got.Dx() != tt.wantW || got.Dy() != tt.wantH {
+				t.Fatalf("缩小后尺寸 %dx%d，期望 %dx%d", got.Dx(), got.Dy(), tt.wantW, tt.wantH)
+			}
+			srcRatio := float64(tt.width) / float64(tt.height)
+			gotRatio := float64(got.Dx()) / float64(got.Dy())
+			if diff := srcRatio - gotRatio; diff > 0.01 || diff < -0.01 {
+				t.Fatalf("宽高比 %.4f 与原图 %.4f 不一致", gotRatio, srcRatio)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package yolo
+
+// 执行提供者名称（ActiveProvider的返回值）
+const (
+	ProviderCPU  = "cpu"
+	ProviderCUDA = "cuda"
+)
+
+// ActiveProvider 返回创建检测器时实际启用的ONNX Runtime执行提供者（如 "cuda"、"cpu"）
+// 用于在界面或日志中确认GPU加速是否真正生效
+func (y *YOLO) ActiveProvider() string {
+	if y.provider == "" {
+		return ProviderCPU
+	}
+	return y.provider
+}
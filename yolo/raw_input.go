@@ -0,0 +1,48 @@
+package yolo
+
+import (
+	"fmt"
+	"image"
+)
+
+// DetectRawRGBA 检测原始RGBA像素缓冲区（每像素4字节，行间无填充），如工业相机SDK返回的帧
+// 直接包装为image.RGBA而不复制或编解码，检测期间调用方不应修改pix
+func (y *YOLO) DetectRawRGBA(pix []byte, width, height int) ([]Detection, error) {
+	if err := checkRawBuffer(pix, width, height, 4); err != nil {
+		return nil, err
+	}
+
+	img := &image.RGBA{
+		Pix:    pix[:width*height*4],
+		Stride: width * 4,
+		Rect:   image.Rect(0, 0, width, height),
+	}
+	return y.detectImage(img)
+}
+
+// DetectRawRGB 检测原始RGB像素缓冲区（每像素3字节，行间无填充），转换为RGBA后检测
+func (y *YOLO) DetectRawRGB(pix []byte, width, height int) ([]Detection, error) {
+	if err := checkRawBuffer(pix, width, height, 3); err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for src, dst := 0, 0; dst < len(img.Pix); src, dst = src+3, dst+4 {
+		img.Pix[dst] = pix[src]
+		img.Pix[dst+1] = pix[src+1]
+		img.Pix[dst+2] = pix[src+2]
+		img.Pix[dst+3] = 255
+	}
+	return y.detectImage(img)
+}
+
+// checkRawBuffer 检查原始像素缓冲区尺寸
+func checkRawBuffer(pix []byte, width, height, channels int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("%w: 无效的图像尺寸 %dx%d", ErrInvalidInput, width, height)
+	}
+	if need := width * height * channels; len(pix) < need {
+		return fmt.Errorf("%w: 像素缓冲区长度 %d 小于 %dx%dx%d=%d", ErrInvalidInput, len(pix), width, height, channels, need)
+	}
+	return nil
+}
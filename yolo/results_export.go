@@ -0,0 +1,357 @@
+package yolo
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Cubiaa/yolo-go/yolo/store"
+)
+
+// SaveClassCountsCSV 按帧保存各类别的检测数量到CSV文件
+// 每行一帧：frame, timestamp（秒）, 每个类别的数量；类别列取自当前类别列表，
+// 不在列表中的类别（如集成模型的命名空间类别）按名称排序追加在后面。
+// 图片结果只输出一行（帧号1，时间戳0）
+func (dr *DetectionResults) SaveClassCountsCSV(path string) error {
+	frames := dr.VideoResults
+	if len(frames) == 0 {
+		if len(dr.Detections) == 0 {
+			return fmt.Errorf("没有检测结果可保存")
+		}
+		frames = []VideoDetectionResult{{FrameNumber: 1, Detections: dr.Detections}}
+	}
+
+	// 列：类别列表 + 列表外出现的类别
+	columns := append([]string(nil), GetClasses()...)
+	known := make(map[string]bool, len(columns))
+	for _, class := range columns {
+		known[class] = true
+	}
+	var extra []string
+	for _, frame := range frames {
+		for _, det := range frame.Detections {
+			if !known[det.Class] {
+				known[det.Class] = true
+				extra = append(extra, det.Class)
+			}
+		}
+	}
+	sort.Strings(extra)
+	columns = append(columns, extra...)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("无法创建CSV文件: %v", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	header := append([]string{"frame", "timestamp"}, columns...)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("写入CSV失败: %v", err)
+	}
+
+	row := make([]string, len(header))
+	for _, frame := range frames {
+		counts := make(map[string]int)
+		for _, det := range frame.Detections {
+			counts[det.Class]++
+		}
+
+		row[0] = strconv.Itoa(frame.FrameNumber)
+		row[1] = strconv.FormatFloat(frame.Timestamp.Seconds(), 'f', 3, 64)
+		for i, class := range columns {
+			row[i+2] = strconv.Itoa(counts[class])
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("写入CSV失败: %v", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("写入CSV失败: %v", err)
+	}
+
+	fmt.Printf("✅ 已保存 %d 帧的类别计数: %s\n", len(frames), path)
+	return nil
+}
+
+// vocAnnotation Pascal VOC标注文件
+type vocAnnotation struct {
+	XMLName   xml.Name    `xml:"annotation"`
+	Folder    string      `xml:"folder"`
+	Filename  string      `xml:"filename"`
+	Path      string      `xml:"path"`
+	Source    vocSource   `xml:"source"`
+	Size      vocSize     `xml:"size"`
+	Segmented int         `xml:"segmented"`
+	Objects   []vocObject `xml:"object"`
+}
+
+type vocSource struct {
+	Database string `xml:"database"`
+}
+
+type vocSize struct {
+	Width  int `xml:"width"`
+	Height int `xml:"height"`
+	Depth  int `xml:"depth"`
+}
+
+type vocObject struct {
+	Name      string    `xml:"name"`
+	Pose      string    `xml:"pose"`
+	Truncated int       `xml:"truncated"`
+	Difficult int       `xml:"difficult"`
+	BndBox    vocBndBox `xml:"bndbox"`
+}
+
+type vocBndBox struct {
+	XMin int `xml:"xmin"`
+	YMin int `xml:"ymin"`
+	XMax int `xml:"xmax"`
+	YMax int `xml:"ymax"`
+}
+
+// SaveVOC 将检测结果保存为Pascal VOC格式的XML标注（可导入LabelImg、CVAT等标注工具进行人工修正）
+// 图片输出一个与图片同名的.xml文件；视频按帧输出 <视频名>_<帧号>.jpg 和对应的.xml文件
+func (dr *DetectionResults) SaveVOC(dir string) error {
+	frames, err := dr.annotationFrames(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, frame := range frames {
+		annotation := vocAnnotation{
+			Folder:   filepath.Base(filepath.Dir(frame.imagePath)),
+			Filename: filepath.Base(frame.imagePath),
+			Path:     frame.imagePath,
+			Source:   vocSource{Database: "yolo-go"},
+			Size:     vocSize{Width: frame.width, Height: frame.height, Depth: 3},
+		}
+		for _, det := range frame.detections {
+			x1, y1, x2, y2 := clampBoxToInt(det.Box, frame.width, frame.height)
+			if x2 <= x1 || y2 <= y1 {
+				continue
+			}
+			annotation.Objects = append(annotation.Objects, vocObject{
+				Name:   det.Class,
+				Pose:   "Unspecified",
+				BndBox: vocBndBox{XMin: x1, YMin: y1, XMax: x2, YMax: y2},
+			})
+		}
+
+		data, err := xml.MarshalIndent(annotation, "", "\t")
+		if err != nil {
+			return fmt.Errorf("生成VOC标注失败: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, frame.name+".xml"), data, 0644); err != nil {
+			return fmt.Errorf("写入VOC标注失败: %v", err)
+		}
+	}
+
+	fmt.Printf("✅ 已保存 %d 个VOC标注文件: %s\n", len(frames), dir)
+	return nil
+}
+
+// SaveYOLOLabels 将检测结果保存为YOLO训练标签格式（每行 class cx cy w h，坐标按图像尺寸归一化）
+// 每张图像一个.txt文件，类别索引取自当前类别列表，可作为半监督训练的伪标签；视频帧的导出方式与SaveVOC相同
+func (dr *DetectionResults) SaveYOLOLabels(dir string) error {
+	frames, err := dr.annotationFrames(dir)
+	if err != nil {
+		return err
+	}
+
+	classIndex := make(map[string]int)
+	for i, class := range GetClasses() {
+		if _, ok := classIndex[class]; !ok {
+			classIndex[class] = i
+		}
+	}
+
+	for _, frame := range frames {
+		var sb strings.Builder
+		for _, det := range frame.detections {
+			id, ok := classIndex[det.Class]
+			if !ok {
+				id = det.ClassID
+			}
+
+			// 限制在图像范围内后归一化
+			w, h := float64(frame.width), float64(frame.height)
+			x1 := math.Max(0, math.Min(w, float64(det.Box[0])))
+			y1 := math.Max(0, math.Min(h, float64(det.Box[1])))
+			x2 := math.Max(0, math.Min(w, float64(det.Box[2])))
+			y2 := math.Max(0, math.Min(h, float64(det.Box[3])))
+			if x2 <= x1 || y2 <= y1 {
+				continue
+			}
+			fmt.Fprintf(&sb, "%d %.6f %.6f %.6f %.6f\n", id,
+				(x1+x2)/2/w, (y1+y2)/2/h, (x2-x1)/w, (y2-y1)/h)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, frame.name+".txt"), []byte(sb.String()), 0644); err != nil {
+			return fmt.Errorf("写入YOLO标签失败: %v", err)
+		}
+	}
+
+	fmt.Printf("✅ 已保存 %d 个YOLO标签文件: %s\n", len(frames), dir)
+	return nil
+}
+
+// annotationFrame 待导出标注的单张图像
+type annotationFrame struct {
+	name          string // 输出文件名（不含扩展名）
+	imagePath     string // 标注对应的图像路径
+	width, height int
+	detections    []Detection
+}
+
+// annotationFrames 收集待导出标注的图像：图片直接使用原图，视频帧保存为JPEG
+func (dr *DetectionResults) annotationFrames(dir string) ([]annotationFrame, error) {
+	if dr.InputPath == "" {
+		return nil, fmt.Errorf("没有输入文件路径信息")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建输出目录失败: %v", err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(dr.InputPath), filepath.Ext(dr.InputPath))
+
+	if !isVideoFile(dr.InputPath) {
+		width, height, err := imageDimensions(dr.InputPath)
+		if err != nil {
+			return nil, err
+		}
+		imagePath, err := filepath.Abs(dr.InputPath)
+		if err != nil {
+			imagePath = dr.InputPath
+		}
+		return []annotationFrame{{name: base, imagePath: imagePath, width: width, height: height, detections: dr.Detections}}, nil
+	}
+
+	if len(dr.VideoResults) == 0 {
+		return nil, fmt.Errorf("没有视频检测结果可保存")
+	}
+
+	frames := make([]annotationFrame, 0, len(dr.VideoResults))
+	for _, result := range dr.VideoResults {
+		if result.Image == nil {
+			return nil, fmt.Errorf("第 %d 帧没有图像数据，无法导出标注", result.FrameNumber)
+		}
+
+		name := fmt.Sprintf("%s_%06d", base, result.FrameNumber)
+		imagePath := filepath.Join(dir, name+".jpg")
+		if err := saveJPEG(imagePath, result.Image); err != nil {
+			return nil, err
+		}
+		if abs, err := filepath.Abs(imagePath); err == nil {
+			imagePath = abs
+		}
+
+		bounds := result.Image.Bounds()
+		frames = append(frames, annotationFrame{
+			name:       name,
+			imagePath:  imagePath,
+			width:      bounds.Dx(),
+			height:     bounds.Dy(),
+			detections: result.Detections,
+		})
+	}
+	return frames, nil
+}
+
+// imageDimensions 读取图片尺寸（仅解析文件头）
+func imageDimensions(path string) (int, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("无法打开图像: %v", err)
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return 0, 0, fmt.Errorf("无法读取图像尺寸: %v", err)
+	}
+	return config.Width, config.Height, nil
+}
+
+// saveJPEG 保存JPEG图像
+func saveJPEG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("无法创建图像文件: %v", err)
+	}
+	defer file.Close()
+
+	if err := jpeg.Encode(file, img, &jpeg.Options{Quality: 95}); err != nil {
+		return fmt.Errorf("保存图像失败: %v", err)
+	}
+	return nil
+}
+
+// clampBoxToInt 将检测框取整并限制在图像范围内
+func clampBoxToInt(box [4]float32, width, height int) (int, int, int, int) {
+	clamp := func(v float32, max int) int {
+		i := int(math.Round(float64(v)))
+		if i < 0 {
+			return 0
+		}
+		if i > max {
+			return max
+		}
+		return i
+	}
+	return clamp(box[0], width), clamp(box[1], height), clamp(box[2], width), clamp(box[3], height)
+}
+
+// SaveSQLite 将检测结果追加写入SQLite数据库（帧号、时间戳、类别、检测框、置信度、跟踪ID）
+// 需要导入SQLite驱动，详见store包说明
+func (dr *DetectionResults) SaveSQLite(dbPath string) error {
+	var records []store.Record
+	appendRecords := func(frame int, timestamp time.Duration, detections []Detection) {
+		for _, det := range detections {
+			records = append(records, store.Record{
+				Source:    dr.InputPath,
+				Frame:     frame,
+				Timestamp: timestamp,
+				Class:     det.Class,
+				ClassID:   det.ClassID,
+				Score:     det.Score,
+				Box:       det.Box,
+				TrackID:   det.TrackID,
+			})
+		}
+	}
+
+	if len(dr.VideoResults) > 0 {
+		for _, result := range dr.VideoResults {
+			appendRecords(result.FrameNumber, result.Timestamp, result.Detections)
+		}
+	} else {
+		appendRecords(0, 0, dr.Detections)
+	}
+
+	db, err := store.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.Insert(records); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ 已写入 %d 条检测记录到数据库: %s\n", len(records), dbPath)
+	return nil
+}
@@ -0,0 +1,116 @@
+package yolo
+
+import "image"
+
+// DetectionFilter 检测结果的链式过滤器，各条件之间为“与”关系
+// 例如: results.Filter().ByClass("person").MinScore(0.7).InRegion(rect).Detections()
+type DetectionFilter struct {
+	source     []Detection
+	predicates []func(Detection) bool
+}
+
+// Filter 创建检测结果过滤器（视频结果会汇总所有帧的检测），不会修改原结果
+func (dr *DetectionResults) Filter() *DetectionFilter {
+	return FilterDetections(dr.allDetections())
+}
+
+// FilterDetections 创建任意检测结果切片的过滤器
+func FilterDetections(detections []Detection) *DetectionFilter {
+	return &DetectionFilter{source: detections}
+}
+
+// Where 添加自定义过滤条件
+func (f *DetectionFilter) Where(predicate func(Detection) bool) *DetectionFilter {
+	f.predicates = append(f.predicates, predicate)
+	return f
+}
+
+// ByClass 仅保留指定类别（任意一个匹配即可）
+func (f *DetectionFilter) ByClass(classes ...string) *DetectionFilter {
+	set := make(map[string]bool, len(classes))
+	for _, class := range classes {
+		set[class] = true
+	}
+	return f.Where(func(d Detection) bool { return set[d.Class] })
+}
+
+// ExcludeClass 排除指定类别
+func (f *DetectionFilter) ExcludeClass(classes ...string) *DetectionFilter {
+	set := make(map[string]bool, len(classes))
+	for _, class := range classes {
+		set[class] = true
+	}
+	return f.Where(func(d Detection) bool { return !set[d.Class] })
+}
+
+// MinScore 仅保留置信度不低于score的检测结果
+func (f *DetectionFilter) MinScore(score float32) *DetectionFilter {
+	return f.Where(func(d Detection) bool { return d.Score >= score })
+}
+
+// MaxScore 仅保留置信度不高于score的检测结果
+func (f *DetectionFilter) MaxScore(score float32) *DetectionFilter {
+	return f.Where(func(d Detection) bool { return d.Score <= score })
+}
+
+// InRegion 仅保留中心点位于区域内的检测结果（原图坐标）
+func (f *DetectionFilter) InRegion(rect image.Rectangle) *DetectionFilter {
+	return f.Where(func(d Detection) bool {
+		cx := (d.Box[0] + d.Box[2]) / 2
+		cy := (d.Box[1] + d.Box[3]) / 2
+		return cx >= float32(rect.Min.X) && cx < float32(rect.Max.X) &&
+			cy >= float32(rect.Min.Y) && cy < float32(rect.Max.Y)
+	})
+}
+
+// MinArea 仅保留面积（像素）不小于area的检测框
+func (f *DetectionFilter) MinArea(area float32) *DetectionFilter {
+	return f.Where(func(d Detection) bool { return boxArea(d.Box) >= area })
+}
+
+// MaxArea 仅保留面积（像素）不大于area的检测框
+func (f *DetectionFilter) MaxArea(area float32) *DetectionFilter {
+	return f.Where(func(d Detection) bool { return boxArea(d.Box) <= area })
+}
+
+// Detections 返回满足所有条件的检测结果（新切片）
+func (f *DetectionFilter) Detections() []Detection {
+	result := make([]Detection, 0, len(f.source))
+	for _, d := range f.source {
+		if f.match(d) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// Count 返回满足所有条件的检测结果数量
+func (f *DetectionFilter) Count() int {
+	count := 0
+	for _, d := range f.source {
+		if f.match(d) {
+			count++
+		}
+	}
+	return count
+}
+
+// match 检查检测结果是否满足所有条件
+func (f *DetectionFilter) match(d Detection) bool {
+	for _, predicate := range f.predicates {
+		if !predicate(d) {
+			return false
+		}
+	}
+	return true
+}
+
+// boxArea 计算检测框面积
+func boxArea(box [4]float32) float32 {
+	w := box[2] - box[0]
+	h := box[3] - box[1]
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	return w * h
+}
@@ -0,0 +1,69 @@
+package yolo
+
+import "sort"
+
+// readingOrderRowOverlap 检测框并入同一行所需的最小垂直重叠比例（相对于较矮的一方）
+const readingOrderRowOverlap = 0.5
+
+// SortReadingOrder 将检测结果按阅读顺序排列（从上到下分行，行内从左到右）
+// 视频结果会对每一帧分别排序，返回自身便于链式调用
+func (dr *DetectionResults) SortReadingOrder() *DetectionResults {
+	SortReadingOrder(dr.Detections)
+	for i := range dr.VideoResults {
+		SortReadingOrder(dr.VideoResults[i].Detections)
+	}
+	return dr
+}
+
+// SortReadingOrder 原地将检测框按阅读顺序排列，适合在OCR前整理文本区域
+// 按垂直重叠把检测框分组为行：与当前行的重叠达到较矮一方高度的一半即视为同一行
+func SortReadingOrder(detections []Detection) {
+	if len(detections) < 2 {
+		return
+	}
+
+	sorted := append([]Detection(nil), detections...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Box[1]+sorted[i].Box[3] < sorted[j].Box[1]+sorted[j].Box[3]
+	})
+
+	// 行的上下边界取成员的平均值，避免个别高框把相邻两行连在一起
+	type row struct {
+		members     []Detection
+		top, bottom float32
+	}
+	var rows []*row
+	for _, det := range sorted {
+		var current *row
+		if len(rows) > 0 {
+			current = rows[len(rows)-1]
+		}
+
+		if current != nil && sameReadingRow(det.Box, current.top, current.bottom) {
+			n := float32(len(current.members))
+			current.top = (current.top*n + det.Box[1]) / (n + 1)
+			current.bottom = (current.bottom*n + det.Box[3]) / (n + 1)
+			current.members = append(current.members, det)
+			continue
+		}
+		rows = append(rows, &row{members: []Detection{det}, top: det.Box[1], bottom: det.Box[3]})
+	}
+
+	i := 0
+	for _, r := range rows {
+		sort.SliceStable(r.members, func(a, b int) bool {
+			return r.members[a].Box[0] < r.members[b].Box[0]
+		})
+		i += copy(detections[i:], r.members)
+	}
+}
+
+// sameReadingRow 判断检测框是否与行在垂直方向上充分重叠
+func sameReadingRow(box [4]float32, top, bottom float32) bool {
+	overlap := minFloat32(box[3], bottom) - maxFloat32(box[1], top)
+	if overlap <= 0 {
+		return false
+	}
+	shorter := minFloat32(box[3]-box[1], bottom-top)
+	return shorter <= 0 || overlap >= readingOrderRowOverlap*shorter
+}
@@ -0,0 +1,49 @@
+package yolo
+
+// ScoreStats 统计检测置信度分布（最小值、最大值、平均值和10档直方图）
+// 直方图按0.1划分区间：histogram[0]为[0,0.1)，histogram[9]为[0.9,1.0]
+// 视频结果会汇总所有帧的检测，便于选择合适的ConfThreshold
+func (dr *DetectionResults) ScoreStats() (min, max, mean float32, histogram [10]int) {
+	detections := dr.allDetections()
+	if len(detections) == 0 {
+		return 0, 0, 0, histogram
+	}
+
+	min = detections[0].Score
+	max = detections[0].Score
+	var sum float64
+	for _, d := range detections {
+		if d.Score < min {
+			min = d.Score
+		}
+		if d.Score > max {
+			max = d.Score
+		}
+		sum += float64(d.Score)
+
+		bin := int(d.Score * 10)
+		if bin < 0 {
+			bin = 0
+		}
+		if bin > 9 {
+			bin = 9
+		}
+		histogram[bin]++
+	}
+	mean = float32(sum / float64(len(detections)))
+
+	return min, max, mean, histogram
+}
+
+// allDetections 返回所有检测结果（视频时汇总逐帧结果）
+func (dr *DetectionResults) allDetections() []Detection {
+	if len(dr.Detections) > 0 || len(dr.VideoResults) == 0 {
+		return dr.Detections
+	}
+
+	var detections []Detection
+	for _, result := range dr.VideoResults {
+		detections = append(detections, result.Detections...)
+	}
+	return detections
+}
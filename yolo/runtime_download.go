@@ -0,0 +1,196 @@
+package yolo
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultRuntimeVersion 与onnxruntime_go v1.21匹配的ONNX Runtime版本
+const DefaultRuntimeVersion = "1.21.0"
+
+// runtimeReleaseURL ONNX Runtime官方发布包下载地址
+const runtimeReleaseURL = "https://github.com/microsoft/onnxruntime/releases/download/v%s/%s"
+
+// runtimeChecksums 官方发布包的SHA-256（按发布包文件名），下载后必须校验通过才会解压加载
+// DefaultRuntimeVersion在runtimePackages每个平台上的发布包都必须有条目（由测试检查），
+// 升级版本时按官方发布页的发布包用sha256sum计算后补充
+var runtimeChecksums = map[string]string{}
+
+// EnsureRuntime 确保dir中存在当前系统和架构对应的ONNX Runtime共享库，不存在时从官方发布页下载
+// 返回库文件路径，可直接传给WithLibraryPath；version为空时使用DefaultRuntimeVersion
+// 下载的发布包按runtimeChecksums中固定的SHA-256校验，没有固定校验值的版本请使用EnsureRuntimeWithChecksum
+func EnsureRuntime(version, dir string) (string, error) {
+	return EnsureRuntimeWithChecksum(version, dir, "")
+}
+
+// EnsureRuntimeWithChecksum 与EnsureRuntime相同，但使用调用方提供的发布包SHA-256（十六进制）校验
+// checksum为空时使用runtimeChecksums中固定的校验值，两者都没有时拒绝下载
+func EnsureRuntimeWithChecksum(version, dir, checksum string) (string, error) {
+	if version == "" {
+		version = DefaultRuntimeVersion
+	}
+
+	archive, libName, err := runtimeArchive(version)
+	if err != nil {
+		return "", err
+	}
+
+	libPath := filepath.Join(dir, libName)
+	if info, err := os.Stat(libPath); err == nil && info.Size() > 0 {
+		return libPath, nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建目录失败: %v", err)
+	}
+
+	if checksum == "" {
+		checksum = runtimeChecksums[archive]
+	}
+	if checksum == "" {
+		return "", fmt.Errorf("%w: %s 没有固定的SHA-256校验值，请通过EnsureRuntimeWithChecksum提供", ErrRuntimeChecksum, archive)
+	}
+
+	url := fmt.Sprintf(runtimeReleaseURL, version, archive)
+	fmt.Printf("⬇️  下载ONNX Runtime %s: %s\n", version, url)
+
+	client := &http.Client{Timeout: 10 * time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("下载ONNX Runtime失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("下载ONNX Runtime失败: %s (%s)", resp.Status, url)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("下载ONNX Runtime失败: %v", err)
+	}
+
+	if err := verifySHA256(data, checksum); err != nil {
+		return "", fmt.Errorf("%w: %s %v", ErrRuntimeChecksum, archive, err)
+	}
+
+	var lib []byte
+	if strings.HasSuffix(archive, ".zip") {
+		lib, err = extractZipFile(data, libName)
+	} else {
+		lib, err = extractTarGzFile(data, libName)
+	}
+	if err != nil {
+		return "", fmt.Errorf("解压ONNX Runtime失败: %v", err)
+	}
+
+	// 先写入临时文件再重命名，避免中断后留下不完整的库文件
+	tmpPath := libPath + ".tmp"
+	if err := os.WriteFile(tmpPath, lib, 0755); err != nil {
+		return "", fmt.Errorf("写入ONNX Runtime库失败: %v", err)
+	}
+	if err := os.Rename(tmpPath, libPath); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("写入ONNX Runtime库失败: %v", err)
+	}
+
+	fmt.Printf("✅ ONNX Runtime已就绪: %s\n", libPath)
+	return libPath, nil
+}
+
+// verifySHA256 校验数据的SHA-256是否与期望的十六进制校验值一致
+func verifySHA256(data []byte, expected string) error {
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return fmt.Errorf("SHA-256不匹配（期望 %s，实际 %s）", expected, actual)
+	}
+	return nil
+}
+
+// runtimePackage 发布包文件名和包内共享库文件名（{version}替换为版本号）
+type runtimePackage struct {
+	archive string
+	lib     string
+}
+
+// runtimePackages EnsureRuntime支持的系统和架构（GOOS/GOARCH）对应的发布包
+var runtimePackages = map[string]runtimePackage{
+	"linux/amd64":   {"onnxruntime-linux-x64-{version}.tgz", "libonnxruntime.so.{version}"},
+	"linux/arm64":   {"onnxruntime-linux-aarch64-{version}.tgz", "libonnxruntime.so.{version}"},
+	"darwin/amd64":  {"onnxruntime-osx-x86_64-{version}.tgz", "libonnxruntime.{version}.dylib"},
+	"darwin/arm64":  {"onnxruntime-osx-arm64-{version}.tgz", "libonnxruntime.{version}.dylib"},
+	"windows/amd64": {"onnxruntime-win-x64-{version}.zip", "onnxruntime.dll"},
+	"windows/arm64": {"onnxruntime-win-arm64-{version}.zip", "onnxruntime.dll"},
+}
+
+// runtimeArchive 返回当前系统和架构对应的发布包文件名和包内共享库文件名
+func runtimeArchive(version string) (string, string, error) {
+	return runtimeArchiveFor(runtime.GOOS+"/"+runtime.GOARCH, version)
+}
+
+// runtimeArchiveFor 返回指定系统和架构（GOOS/GOARCH）对应的发布包文件名和包内共享库文件名
+func runtimeArchiveFor(platform, version string) (string, string, error) {
+	pkg, ok := runtimePackages[platform]
+	if !ok {
+		return "", "", fmt.Errorf("没有适用于 %s 的ONNX Runtime预编译包", platform)
+	}
+	return strings.ReplaceAll(pkg.archive, "{version}", version), strings.ReplaceAll(pkg.lib, "{version}", version), nil
+}
+
+// extractTarGzFile 从tar.gz包中读取指定文件名的文件
+func extractTarGzFile(data []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == name {
+			return io.ReadAll(tr)
+		}
+	}
+	return nil, fmt.Errorf("发布包中未找到 %s", name)
+}
+
+// extractZipFile 从zip包中读取指定文件名的文件
+func extractZipFile(data []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range zr.File {
+		if path.Base(file.Name) != name || file.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("发布包中未找到 %s", name)
+}
@@ -0,0 +1,24 @@
+package yolo
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// TestRuntimeChecksumsCoverDefaultVersion EnsureRuntime可能选择的每个DefaultRuntimeVersion发布包都固定了SHA-256
+func TestRuntimeChecksumsCoverDefaultVersion(t *testing.T) {
+	for platform := range runtimePackages {
+		archive, _, err := runtimeArchiveFor(platform, DefaultRuntimeVersion)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum, ok := runtimeChecksums[archive]
+		if !ok {
+			t.Errorf("%s（%s）没有固定的SHA-256校验值", archive, platform)
+			continue
+		}
+		if decoded, err := hex.DecodeString(sum); err != nil || len(decoded) != 32 {
+			t.Errorf("%s 的SHA-256校验值格式无效: %q", archive, sum)
+		}
+	}
+}
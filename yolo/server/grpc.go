@@ -0,0 +1,107 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/Cubiaa/yolo-go/yolo"
+)
+
+// StreamDetect 服务和方法名（与detection.proto一致）
+const (
+	DetectionServiceName = "yolo.DetectionService"
+	StreamDetectMethod   = "/yolo.DetectionService/StreamDetect"
+)
+
+// detectionServiceDesc 流式检测服务描述
+var detectionServiceDesc = grpc.ServiceDesc{
+	ServiceName: DetectionServiceName,
+	HandlerType: (*interface{})(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamDetect",
+			Handler:       streamDetectHandler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "detection.proto",
+}
+
+// NewGRPCServer 创建已注册流式检测服务的gRPC服务器
+func (s *Server) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ForceServerCodec(Codec()))
+	grpcServer := grpc.NewServer(opts...)
+	grpcServer.RegisterService(&detectionServiceDesc, s)
+	return grpcServer
+}
+
+// StartGRPCServer 在指定地址启动gRPC流式检测服务
+// 客户端通过 StreamDetect 持续发送帧，服务端逐帧返回检测结果
+func StartGRPCServer(detector *yolo.YOLO, addr string) (*grpc.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("启动gRPC检测服务失败: %v", err)
+	}
+
+	grpcServer := NewServer(detector).NewGRPCServer()
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			fmt.Printf("❌ gRPC检测服务异常退出: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("🌐 gRPC检测服务已启动: %s\n", listener.Addr())
+	return grpcServer, nil
+}
+
+// streamDetectHandler StreamDetect双向流处理
+func streamDetectHandler(srv interface{}, stream grpc.ServerStream) error {
+	s := srv.(*Server)
+	for {
+		frame := &Frame{}
+		if err := stream.RecvMsg(frame); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		if err := stream.SendMsg(s.detectFrame(frame)); err != nil {
+			return err
+		}
+	}
+}
+
+// detectFrame 检测单帧，检测失败时在结果中返回错误信息而不中断流
+func (s *Server) detectFrame(frame *Frame) *DetectionResult {
+	start := time.Now()
+	detections, err := s.detectBytes(frame.Image)
+
+	result := FromVideoDetectionResult(yolo.VideoDetectionResult{
+		FrameNumber:    int(frame.FrameNumber),
+		Timestamp:      time.Duration(frame.TimestampUs) * time.Microsecond,
+		Detections:     detections,
+		ProcessingTime: time.Since(start),
+	})
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// detectBytes 检测编码后的图像数据
+func (s *Server) detectBytes(data []byte) ([]yolo.Detection, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("图像数据为空")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.detector.DetectImageReader(bytes.NewReader(data))
+}
@@ -0,0 +1,304 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/Cubiaa/yolo-go/yolo"
+)
+
+// Frame 输入帧（对应detection.proto中的Frame）
+type Frame struct {
+	Image       []byte // JPEG或PNG编码的图像
+	FrameNumber int64  // 帧号（原样返回）
+	TimestampUs int64  // 帧时间戳（微秒，原样返回）
+}
+
+// DetectionMessage 单个检测结果（对应detection.proto中的Detection）
+type DetectionMessage struct {
+	Box     [4]float32 // x1, y1, x2, y2
+	Score   float32
+	ClassID int32
+	Class   string
+}
+
+// DetectionResult 单帧检测结果（对应detection.proto中的DetectionResult）
+type DetectionResult struct {
+	FrameNumber      int64
+	TimestampUs      int64
+	Detections       []DetectionMessage
+	ProcessingTimeUs int64
+	Error            string
+}
+
+// FromVideoDetectionResult 将视频帧检测结果转换为消息
+func FromVideoDetectionResult(result yolo.VideoDetectionResult) *DetectionResult {
+	msg := &DetectionResult{
+		FrameNumber:      int64(result.FrameNumber),
+		TimestampUs:      result.Timestamp.Microseconds(),
+		ProcessingTimeUs: result.ProcessingTime.Microseconds(),
+		Detections:       make([]DetectionMessage, 0, len(result.Detections)),
+	}
+	for _, det := range result.Detections {
+		msg.Detections = append(msg.Detections, DetectionMessage{
+			Box:     det.Box,
+			Score:   det.Score,
+			ClassID: int32(det.ClassID),
+			Class:   det.Class,
+		})
+	}
+	return msg
+}
+
+// ToVideoDetectionResult 将消息转换为视频帧检测结果（不含图像）
+func (r *DetectionResult) ToVideoDetectionResult() yolo.VideoDetectionResult {
+	result := yolo.VideoDetectionResult{
+		FrameNumber:    int(r.FrameNumber),
+		Timestamp:      time.Duration(r.TimestampUs) * time.Microsecond,
+		ProcessingTime: time.Duration(r.ProcessingTimeUs) * time.Microsecond,
+		Detections:     make([]yolo.Detection, 0, len(r.Detections)),
+	}
+	for _, det := range r.Detections {
+		result.Detections = append(result.Detections, yolo.Detection{
+			Box:     det.Box,
+			Score:   det.Score,
+			ClassID: int(det.ClassID),
+			Class:   det.Class,
+		})
+	}
+	return result
+}
+
+// codec 按detection.proto的protobuf线格式编解码消息，无需protoc生成代码
+type codec struct{}
+
+// codecName 编解码器名称，使用私有名称，避免与全局注册的标准"proto"编解码器冲突
+const codecName = "yolo-detection"
+
+// Codec 返回流式检测服务使用的编解码器（只通过ForceServerCodec/ForceCodec使用，不做全局注册）
+// Go客户端可通过 grpc.WithDefaultCallOptions(grpc.ForceCodec(server.Codec())) 使用；
+// 线格式与detection.proto一致，其他语言可直接使用protoc生成的代码
+func Codec() codec {
+	return codec{}
+}
+
+// Name 编解码器名称
+func (codec) Name() string {
+	return codecName
+}
+
+// Marshal 编码消息
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	switch msg := v.(type) {
+	case *Frame:
+		return msg.marshal(), nil
+	case *DetectionResult:
+		return msg.marshal(), nil
+	default:
+		return nil, fmt.Errorf("不支持的消息类型: %T", v)
+	}
+}
+
+// Unmarshal 解码消息
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	switch msg := v.(type) {
+	case *Frame:
+		return msg.unmarshal(data)
+	case *DetectionResult:
+		return msg.unmarshal(data)
+	default:
+		return fmt.Errorf("不支持的消息类型: %T", v)
+	}
+}
+
+// marshal 编码Frame
+func (f *Frame) marshal() []byte {
+	var b []byte
+	if len(f.Image) > 0 {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, f.Image)
+	}
+	if f.FrameNumber != 0 {
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(f.FrameNumber))
+	}
+	if f.TimestampUs != 0 {
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(f.TimestampUs))
+	}
+	return b
+}
+
+// unmarshal 解码Frame
+func (f *Frame) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			f.Image = append([]byte(nil), v...)
+			return n, nil
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			f.FrameNumber = int64(v)
+			return n, nil
+		case num == 3 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			f.TimestampUs = int64(v)
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, b), nil
+	})
+}
+
+// marshal 编码DetectionMessage
+func (d *DetectionMessage) marshal() []byte {
+	var b []byte
+
+	// repeated float 使用packed编码
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendVarint(b, uint64(len(d.Box)*4))
+	for _, v := range d.Box {
+		b = protowire.AppendFixed32(b, math.Float32bits(v))
+	}
+
+	if d.Score != 0 {
+		b = protowire.AppendTag(b, 2, protowire.Fixed32Type)
+		b = protowire.AppendFixed32(b, math.Float32bits(d.Score))
+	}
+	if d.ClassID != 0 {
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(d.ClassID))
+	}
+	if d.Class != "" {
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendString(b, d.Class)
+	}
+	return b
+}
+
+// unmarshal 解码DetectionMessage
+func (d *DetectionMessage) unmarshal(b []byte) error {
+	boxIndex := 0
+	appendBox := func(v uint32) {
+		if boxIndex < len(d.Box) {
+			d.Box[boxIndex] = math.Float32frombits(v)
+			boxIndex++
+		}
+	}
+
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			packed, n := protowire.ConsumeBytes(b)
+			for len(packed) >= 4 {
+				v, m := protowire.ConsumeFixed32(packed)
+				appendBox(v)
+				packed = packed[m:]
+			}
+			return n, nil
+		case num == 1 && typ == protowire.Fixed32Type:
+			v, n := protowire.ConsumeFixed32(b)
+			appendBox(v)
+			return n, nil
+		case num == 2 && typ == protowire.Fixed32Type:
+			v, n := protowire.ConsumeFixed32(b)
+			d.Score = math.Float32frombits(v)
+			return n, nil
+		case num == 3 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			d.ClassID = int32(v)
+			return n, nil
+		case num == 4 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			d.Class = v
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, b), nil
+	})
+}
+
+// marshal 编码DetectionResult
+func (r *DetectionResult) marshal() []byte {
+	var b []byte
+	if r.FrameNumber != 0 {
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(r.FrameNumber))
+	}
+	if r.TimestampUs != 0 {
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(r.TimestampUs))
+	}
+	for i := range r.Detections {
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, r.Detections[i].marshal())
+	}
+	if r.ProcessingTimeUs != 0 {
+		b = protowire.AppendTag(b, 4, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(r.ProcessingTimeUs))
+	}
+	if r.Error != "" {
+		b = protowire.AppendTag(b, 5, protowire.BytesType)
+		b = protowire.AppendString(b, r.Error)
+	}
+	return b
+}
+
+// unmarshal 解码DetectionResult
+func (r *DetectionResult) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			r.FrameNumber = int64(v)
+			return n, nil
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			r.TimestampUs = int64(v)
+			return n, nil
+		case num == 3 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return n, nil
+			}
+			var det DetectionMessage
+			if err := det.unmarshal(v); err != nil {
+				return 0, err
+			}
+			r.Detections = append(r.Detections, det)
+			return n, nil
+		case num == 4 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			r.ProcessingTimeUs = int64(v)
+			return n, nil
+		case num == 5 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			r.Error = v
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, b), nil
+	})
+}
+
+// consumeFields 遍历消息中的所有字段，field返回已消费的字节数（负数表示解析错误）
+func consumeFields(b []byte, field func(num protowire.Number, typ protowire.Type, b []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		n, err := field(num, typ, b)
+		if err != nil {
+			return err
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	return nil
+}
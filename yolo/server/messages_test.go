@@ -0,0 +1,171 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// detectionProtoFile 按detection.proto构建的文件描述，供标准protobuf实现编解码对照
+func detectionProtoFile(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+
+	field := func(name string, num int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(num),
+			Type:     typ.Enum(),
+			Label:    label.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("detection.proto"),
+		Package: proto.String("yolo"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Frame"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("image", 1, descriptorpb.FieldDescriptorProto_TYPE_BYTES, optional, ""),
+					field("frame_number", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+					field("timestamp_us", 3, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+				},
+			},
+			{
+				Name: proto.String("Detection"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("box", 1, descriptorpb.FieldDescriptorProto_TYPE_FLOAT, repeated, ""),
+					field("score", 2, descriptorpb.FieldDescriptorProto_TYPE_FLOAT, optional, ""),
+					field("class_id", 3, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional, ""),
+					field("class", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+				},
+			},
+			{
+				Name: proto.String("DetectionResult"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("frame_number", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+					field("timestamp_us", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+					field("detections", 3, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".yolo.Detection"),
+					field("processing_time_us", 4, descriptorpb.FieldDescriptorProto_TYPE_INT64, optional, ""),
+					field("error", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, ""),
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(file, nil)
+	if err != nil {
+		t.Fatalf("构建detection.proto描述失败: %v", err)
+	}
+	return fd
+}
+
+// TestCodecFrameMatchesProto Frame的编码与标准protobuf实现互通
+func TestCodecFrameMatchesProto(t *testing.T) {
+	desc := detectionProtoFile(t).Messages().ByName("Frame")
+	fields := desc.Fields()
+
+	// 标准实现编码 -> 本编解码器解码
+	ref := dynamicpb.NewMessage(desc)
+	ref.Set(fields.ByName("image"), protoreflect.ValueOfBytes([]byte{0xff, 0xd8, 0x00, 0x01}))
+	ref.Set(fields.ByName("frame_number"), protoreflect.ValueOfInt64(42))
+	ref.Set(fields.ByName("timestamp_us"), protoreflect.ValueOfInt64(-1500))
+	data, err := proto.Marshal(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var frame Frame
+	if err := Codec().Unmarshal(data, &frame); err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	want := Frame{Image: []byte{0xff, 0xd8, 0x00, 0x01}, FrameNumber: 42, TimestampUs: -1500}
+	if !reflect.DeepEqual(frame, want) {
+		t.Fatalf("解码结果 %+v，期望 %+v", frame, want)
+	}
+
+	// 本编解码器编码 -> 标准实现解码
+	encoded, err := Codec().Marshal(&want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := dynamicpb.NewMessage(desc)
+	if err := proto.Unmarshal(encoded, decoded); err != nil {
+		t.Fatalf("标准实现解码失败: %v", err)
+	}
+	if !proto.Equal(decoded, ref) {
+		t.Fatalf("标准实现解码结果 %v，期望 %v", decoded, ref)
+	}
+}
+
+// TestCodecDetectionResultMatchesProto DetectionResult（含嵌套Detection和packed浮点数组）与标准protobuf实现互通
+func TestCodecDetectionResultMatchesProto(t *testing.T) {
+	file := detectionProtoFile(t)
+	resultDesc := file.Messages().ByName("DetectionResult")
+	detDesc := file.Messages().ByName("Detection")
+
+	want := DetectionResult{
+		FrameNumber: 7,
+		TimestampUs: 233000,
+		Detections: []DetectionMessage{
+			{Box: [4]float32{1.5, 2, 30.25, 40}, Score: 0.875, ClassID: 2, Class: "car"},
+			{Box: [4]float32{0, 0, 10, 10}, Score: 0.5, ClassID: 0, Class: "人"},
+		},
+		ProcessingTimeUs: 1234,
+		Error:            "",
+	}
+
+	ref := dynamicpb.NewMessage(resultDesc)
+	ref.Set(resultDesc.Fields().ByName("frame_number"), protoreflect.ValueOfInt64(want.FrameNumber))
+	ref.Set(resultDesc.Fields().ByName("timestamp_us"), protoreflect.ValueOfInt64(want.TimestampUs))
+	ref.Set(resultDesc.Fields().ByName("processing_time_us"), protoreflect.ValueOfInt64(want.ProcessingTimeUs))
+	list := ref.Mutable(resultDesc.Fields().ByName("detections")).List()
+	for _, d := range want.Detections {
+		det := dynamicpb.NewMessage(detDesc)
+		box := det.Mutable(detDesc.Fields().ByName("box")).List()
+		for _, v := range d.Box {
+			box.Append(protoreflect.ValueOfFloat32(v))
+		}
+		det.Set(detDesc.Fields().ByName("score"), protoreflect.ValueOfFloat32(d.Score))
+		det.Set(detDesc.Fields().ByName("class_id"), protoreflect.ValueOfInt32(d.ClassID))
+		det.Set(detDesc.Fields().ByName("class"), protoreflect.ValueOfString(d.Class))
+		list.Append(protoreflect.ValueOfMessage(det))
+	}
+
+	data, err := proto.Marshal(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got DetectionResult
+	if err := Codec().Unmarshal(data, &got); err != nil {
+		t.Fatalf("解码失败: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("解码结果 %+v，期望 %+v", got, want)
+	}
+
+	encoded, err := Codec().Marshal(&want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := dynamicpb.NewMessage(resultDesc)
+	if err := proto.Unmarshal(encoded, decoded); err != nil {
+		t.Fatalf("标准实现解码失败: %v", err)
+	}
+	if !proto.Equal(decoded, ref) {
+		t.Fatalf("标准实现解码结果 %v，期望 %v", decoded, ref)
+	}
+}
@@ -0,0 +1,195 @@
+// Package server 提供基于HTTP和gRPC的YOLO检测服务
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/jpeg"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Cubiaa/yolo-go/yolo"
+)
+
+// DefaultMaxUploadSize 默认最大上传图片大小（32MB）
+const DefaultMaxUploadSize = 32 << 20
+
+// Server 检测服务（HTTP和gRPC共用同一检测器）
+type Server struct {
+	detector      *yolo.YOLO
+	mu            sync.Mutex // 检测器非并发安全，串行执行检测
+	MaxUploadSize int64      // 最大上传大小（字节）
+}
+
+// DetectionJSON 检测结果的JSON表示
+type DetectionJSON struct {
+	Class   string     `json:"class"`
+	ClassID int        `json:"class_id"`
+	Score   float32    `json:"score"`
+	Box     [4]float32 `json:"box"` // x1, y1, x2, y2
+
+	Attributes map[string]string `json:"attributes,omitempty"` // 后处理器附加的属性
+}
+
+// DetectResponse POST /detect 的JSON响应
+type DetectResponse struct {
+	Detections []DetectionJSON `json:"detections"`
+	Count      int             `json:"count"`
+	TimeMs     float64         `json:"time_ms"`
+}
+
+// errorResponse 错误响应
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewServer 创建HTTP检测服务
+func NewServer(detector *yolo.YOLO) *Server {
+	return &Server{
+		detector:      detector,
+		MaxUploadSize: DefaultMaxUploadSize,
+	}
+}
+
+// Handler 返回服务的HTTP路由
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/detect", s.handleDetect)
+	return mux
+}
+
+// StartHTTPServer 在指定地址启动检测服务
+// POST /detect 上传图片（multipart字段image/file或原始请求体）返回JSON检测结果
+// POST /detect?draw=1 返回绘制检测框后的JPEG图片
+func StartHTTPServer(detector *yolo.YOLO, addr string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("启动HTTP检测服务失败: %v", err)
+	}
+
+	server := &http.Server{
+		Handler:           NewServer(detector).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("❌ HTTP检测服务异常退出: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("🌐 HTTP检测服务已启动: http://%s/detect\n", listener.Addr())
+	return server, nil
+}
+
+// handleDetect 处理检测请求
+func (s *Server) handleDetect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "仅支持POST请求")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.MaxUploadSize)
+
+	// 直接从请求体解码图像，不经过临时文件
+	src, err := uploadReader(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer src.Close()
+
+	draw, _ := strconv.ParseBool(r.URL.Query().Get("draw"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := time.Now()
+	if draw {
+		annotated, detections, err := s.detector.DetectAndAnnotateReader(src)
+		if err != nil {
+			writeError(w, statusForError(err), fmt.Sprintf("检测失败: %v", err))
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Header().Set("X-Detection-Count", strconv.Itoa(len(detections)))
+		jpeg.Encode(w, annotated, &jpeg.Options{Quality: 90})
+		return
+	}
+
+	detections, err := s.detector.DetectImageReader(src)
+	if err != nil {
+		writeError(w, statusForError(err), fmt.Sprintf("检测失败: %v", err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, DetectResponse{
+		Detections: toDetectionJSON(detections),
+		Count:      len(detections),
+		TimeMs:     float64(time.Since(start).Microseconds()) / 1000,
+	})
+}
+
+// uploadReader 返回请求中的图片数据：multipart表单取image或file字段，否则为整个请求体
+func uploadReader(r *http.Request) (io.ReadCloser, error) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.Body, nil
+	}
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		file, _, err = r.FormFile("file")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("未找到上传图片（字段名image或file）: %v", err)
+	}
+	return file, nil
+}
+
+// toDetectionJSON 转换检测结果为JSON结构
+func toDetectionJSON(detections []yolo.Detection) []DetectionJSON {
+	result := make([]DetectionJSON, 0, len(detections))
+	for _, det := range detections {
+		result = append(result, DetectionJSON{
+			Class:   det.Class,
+			ClassID: det.ClassID,
+			Score:   det.Score,
+			Box:     det.Box,
+
+			Attributes: det.Attributes,
+		})
+	}
+	return result
+}
+
+// statusForError 根据检测错误类型选择HTTP状态码：输入问题返回400，GPU不可用返回503，其余返回500
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, yolo.ErrInvalidInput), errors.Is(err, yolo.ErrUnsupportedFormat):
+		return http.StatusBadRequest
+	case errors.Is(err, yolo.ErrGPUUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeJSON 输出JSON响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError 输出JSON错误响应
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
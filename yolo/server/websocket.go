@@ -0,0 +1,265 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/Cubiaa/yolo-go/yolo"
+)
+
+// wsClientBuffer 每个WebSocket客户端的待发送帧缓冲，客户端过慢时丢弃新帧而不阻塞检测
+const wsClientBuffer = 16
+
+// FrameJSON WebSocket推送的单帧检测结果
+type FrameJSON struct {
+	FrameNumber  int             `json:"frame"`
+	TimestampMs  float64         `json:"timestamp_ms"`
+	ProcessingMs float64         `json:"processing_ms"`
+	Detections   []DetectionJSON `json:"detections"`
+	Count        int             `json:"count"`
+}
+
+// WebSocketServer 通过WebSocket向浏览器客户端实时推送视频/流的逐帧检测结果
+// 同一时间只运行一个检测源，所有客户端共享同一路结果
+type WebSocketServer struct {
+	detector *yolo.YOLO
+	Options  *yolo.DetectionOptions // 检测选项（nil表示默认选项）
+
+	// AllowedSources 客户端可通过 ?source= 启动的检测源白名单（需完全匹配）
+	// 默认为空，即不允许客户端指定检测源，只能由服务端调用Stream或StartWebSocketServer启动
+	AllowedSources []string
+	// AllowedOrigins 允许跨域连接的Origin（如 "https://example.com"），同源和不带Origin的非浏览器客户端始终允许
+	AllowedOrigins []string
+
+	mu        sync.Mutex
+	clients   map[*websocket.Conn]chan []byte
+	streaming bool
+}
+
+// NewWebSocketServer 创建WebSocket检测结果推送服务
+func NewWebSocketServer(detector *yolo.YOLO) *WebSocketServer {
+	return &WebSocketServer{
+		detector: detector,
+		clients:  make(map[*websocket.Conn]chan []byte),
+	}
+}
+
+// Handler 返回服务的HTTP路由
+// GET /ws 建立WebSocket连接；带 ?source= 参数、该源在AllowedSources中且当前没有检测源时启动该源
+// 来自其他站点的浏览器连接（Origin不同源且不在AllowedOrigins中）在握手时返回403
+func (s *WebSocketServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/ws", websocket.Server{
+		Handler:   s.handleConn,
+		Handshake: s.checkOrigin,
+	})
+	return mux
+}
+
+// checkOrigin 握手时校验Origin，防止任意网页借助浏览器连接本服务
+func (s *WebSocketServer) checkOrigin(config *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return fmt.Errorf("无效的Origin: %v", err)
+	}
+	if strings.EqualFold(u.Host, req.Host) {
+		return nil
+	}
+	for _, allowed := range s.AllowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return nil
+		}
+	}
+	return fmt.Errorf("不允许的Origin: %s", origin)
+}
+
+// sourceAllowed 客户端请求的检测源是否在白名单中
+func (s *WebSocketServer) sourceAllowed(source string) bool {
+	for _, allowed := range s.AllowedSources {
+		if source == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// StartWebSocketServer 在指定地址启动WebSocket推送服务，source不为空时立即开始检测该源
+// source支持视频文件路径、rtsp://、rtmp://、camera:<设备> 和 screen；客户端不能通过 ?source= 指定其他源
+func StartWebSocketServer(detector *yolo.YOLO, addr string, source ...string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("启动WebSocket推送服务失败: %v", err)
+	}
+
+	ws := NewWebSocketServer(detector)
+	server := &http.Server{
+		Handler:           ws.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("❌ WebSocket推送服务异常退出: %v\n", err)
+		}
+	}()
+
+	if len(source) > 0 && source[0] != "" {
+		go ws.streamInBackground(source[0])
+	}
+
+	fmt.Printf("🌐 WebSocket推送服务已启动: ws://%s/ws\n", listener.Addr())
+	return server, nil
+}
+
+// Stream 检测指定源并将每帧结果推送给所有已连接客户端，阻塞直到源结束
+func (s *WebSocketServer) Stream(source string) error {
+	s.mu.Lock()
+	if s.streaming {
+		s.mu.Unlock()
+		return fmt.Errorf("已有检测源正在运行")
+	}
+	s.streaming = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.streaming = false
+		s.mu.Unlock()
+	}()
+
+	if err := s.runSource(source); err != nil {
+		s.broadcastJSON(errorResponse{Error: err.Error()})
+		return err
+	}
+	return nil
+}
+
+// Broadcast 推送一帧检测结果给所有已连接客户端
+func (s *WebSocketServer) Broadcast(result yolo.VideoDetectionResult) {
+	s.broadcastJSON(FrameJSON{
+		FrameNumber:  result.FrameNumber,
+		TimestampMs:  float64(result.Timestamp.Microseconds()) / 1000,
+		ProcessingMs: float64(result.ProcessingTime.Microseconds()) / 1000,
+		Detections:   toDetectionJSON(result.Detections),
+		Count:        len(result.Detections),
+	})
+}
+
+// ClientCount 返回当前连接的客户端数量
+func (s *WebSocketServer) ClientCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+// streamInBackground 运行检测源并输出结束原因
+func (s *WebSocketServer) streamInBackground(source string) {
+	if err := s.Stream(source); err != nil {
+		fmt.Printf("⚠️  WebSocket检测源 %s 结束: %v\n", source, err)
+		return
+	}
+	fmt.Printf("✅ WebSocket检测源 %s 已结束\n", source)
+}
+
+// isStreaming 是否有检测源正在运行
+func (s *WebSocketServer) isStreaming() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streaming
+}
+
+// runSource 按源类型选择检测入口
+func (s *WebSocketServer) runSource(source string) error {
+	var err error
+	switch {
+	case strings.HasPrefix(source, "rtsp://"):
+		_, err = s.detector.DetectFromRTSP(source, s.Options, s.Broadcast)
+	case strings.HasPrefix(source, "rtmp://"):
+		_, err = s.detector.DetectFromRTMP(source, s.Options, s.Broadcast)
+	case strings.HasPrefix(source, "camera:"):
+		_, err = s.detector.DetectFromCamera(strings.TrimPrefix(source, "camera:"), s.Options, s.Broadcast)
+	case source == "screen":
+		_, err = s.detector.DetectFromScreen(s.Options, s.Broadcast)
+	default:
+		_, err = s.detector.Detect(source, s.Options, s.Broadcast)
+	}
+	return err
+}
+
+// broadcastJSON 编码消息并放入每个客户端的发送缓冲
+func (s *WebSocketServer) broadcastJSON(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, send := range s.clients {
+		select {
+		case send <- data:
+		default:
+			// 客户端过慢，丢弃该帧
+		}
+	}
+}
+
+// handleConn 处理单个WebSocket连接
+func (s *WebSocketServer) handleConn(conn *websocket.Conn) {
+	send := make(chan []byte, wsClientBuffer)
+
+	s.mu.Lock()
+	s.clients[conn] = send
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	// 已有检测源时新客户端直接加入，忽略source参数；不在白名单中的源拒绝启动
+	if source := conn.Request().URL.Query().Get("source"); source != "" {
+		if !s.sourceAllowed(source) {
+			if data, err := json.Marshal(errorResponse{Error: fmt.Sprintf("不允许的检测源: %s", source)}); err == nil {
+				send <- data
+			}
+		} else if !s.isStreaming() {
+			go s.streamInBackground(source)
+		}
+	}
+
+	// 客户端不发送数据，读取只用于感知连接关闭
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard string
+		for websocket.Message.Receive(conn, &discard) == nil {
+		}
+	}()
+
+	for {
+		select {
+		case data := <-send:
+			if err := websocket.Message.Send(conn, string(data)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
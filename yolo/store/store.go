@@ -0,0 +1,116 @@
+// Package store 将检测结果写入SQLite数据库，便于按类别和时间查询历史检测数据
+//
+// 本包通过database/sql访问数据库，不内置SQLite驱动，使用前需导入驱动，例如:
+//
+//	import _ "github.com/mattn/go-sqlite3"     // DriverName = "sqlite3"（默认）
+//	import _ "modernc.org/sqlite"              // 纯Go实现，需设置 store.DriverName = "sqlite"
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DriverName 使用的database/sql驱动名
+var DriverName = "sqlite3"
+
+// schema 检测结果表结构，按类别+时间和写入时间建立索引
+const schema = `
+CREATE TABLE IF NOT EXISTS detections (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	source       TEXT    NOT NULL,
+	frame        INTEGER NOT NULL,
+	timestamp_ms INTEGER NOT NULL,
+	class        TEXT    NOT NULL,
+	class_id     INTEGER NOT NULL,
+	score        REAL    NOT NULL,
+	x1           REAL    NOT NULL,
+	y1           REAL    NOT NULL,
+	x2           REAL    NOT NULL,
+	y2           REAL    NOT NULL,
+	track_id     INTEGER,
+	created_at   INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_detections_class_time ON detections (class, timestamp_ms);
+CREATE INDEX IF NOT EXISTS idx_detections_created_at ON detections (created_at);
+CREATE INDEX IF NOT EXISTS idx_detections_source_frame ON detections (source, frame);
+`
+
+// Record 一条检测记录
+type Record struct {
+	Source    string        // 输入来源（文件路径或流地址）
+	Frame     int           // 帧号（图片为0）
+	Timestamp time.Duration // 视频内时间戳（图片为0）
+	Class     string
+	ClassID   int
+	Score     float32
+	Box       [4]float32 // x1, y1, x2, y2
+	TrackID   int        // 跟踪ID（0表示未跟踪，写入为NULL）
+}
+
+// Store SQLite检测结果存储
+type Store struct {
+	db *sql.DB
+}
+
+// Open 打开（不存在时创建）数据库并初始化表结构
+func Open(dbPath string) (*Store, error) {
+	db, err := sql.Open(DriverName, dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("打开数据库失败（是否已导入 %s 驱动？）: %v", DriverName, err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("初始化数据库表失败: %v", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// DB 返回底层数据库连接，用于自定义查询
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Insert 在一个事务中批量写入检测记录
+func (s *Store) Insert(records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO detections
+		(source, frame, timestamp_ms, class, class_id, score, x1, y1, x2, y2, track_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("准备插入语句失败: %v", err)
+	}
+	defer stmt.Close()
+
+	createdAt := time.Now().UnixMilli()
+	for _, r := range records {
+		var trackID interface{}
+		if r.TrackID > 0 {
+			trackID = r.TrackID
+		}
+		if _, err := stmt.Exec(r.Source, r.Frame, r.Timestamp.Milliseconds(), r.Class, r.ClassID, r.Score,
+			r.Box[0], r.Box[1], r.Box[2], r.Box[3], trackID, createdAt); err != nil {
+			return fmt.Errorf("写入检测记录失败: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %v", err)
+	}
+	return nil
+}
+
+// Close 关闭数据库
+func (s *Store) Close() error {
+	return s.db.Close()
+}
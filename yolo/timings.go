@@ -0,0 +1,55 @@
+package yolo
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timings 单次检测各阶段耗时
+type Timings struct {
+	Preprocess  time.Duration // 图像解码、缩放和归一化
+	Inference   time.Duration // 模型推理（ONNX Runtime执行）
+	Postprocess time.Duration // 其余耗时：输出解析、坐标换算、NMS和后处理
+	Total       time.Duration // 总耗时
+}
+
+// String 返回各阶段耗时摘要
+func (t Timings) String() string {
+	return fmt.Sprintf("preprocess=%v inference=%v postprocess=%v total=%v",
+		t.Preprocess, t.Inference, t.Postprocess, t.Total)
+}
+
+// LastTimings 返回最近一次DetectImage/DetectImageImage等单帧检测的各阶段耗时
+func (y *YOLO) LastTimings() Timings {
+	y.timingsMu.Lock()
+	defer y.timingsMu.Unlock()
+	return y.timings
+}
+
+// finishTimings 计算本次检测的总耗时（未单独统计的部分计入后处理）并记为最近一次耗时
+// 各阶段耗时在调用方栈上的Timings中累加，只有单帧检测路径记录，批量检测的并发调用传nil
+func (y *YOLO) finishTimings(t *Timings, start time.Time) {
+	t.Total = time.Since(start)
+	t.Postprocess = t.Total - t.Preprocess - t.Inference
+	if t.Postprocess < 0 {
+		t.Postprocess = 0
+	}
+
+	y.timingsMu.Lock()
+	y.timings = *t
+	y.timingsMu.Unlock()
+}
+
+// addPreprocess 累加预处理耗时（t为nil时不统计）
+func (t *Timings) addPreprocess(d time.Duration) {
+	if t != nil {
+		t.Preprocess += d
+	}
+}
+
+// addInference 累加推理耗时（t为nil时不统计）
+func (t *Timings) addInference(d time.Duration) {
+	if t != nil {
+		t.Inference += d
+	}
+}
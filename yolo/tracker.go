@@ -0,0 +1,95 @@
+package yolo
+
+import (
+	"sort"
+	"sync"
+)
+
+const (
+	// trackerIOUThreshold 检测框与已有轨迹匹配所需的最小IOU
+	trackerIOUThreshold = 0.3
+	// trackerMaxMissed 轨迹连续未匹配超过该帧数后丢弃，目标再次出现时分配新ID
+	trackerMaxMissed = 30
+)
+
+// trackedObject 跟踪中的目标
+type trackedObject struct {
+	id      int
+	classID int
+	box     [4]float32 // 最近一次匹配到的检测框
+	missed  int        // 连续未匹配的帧数
+}
+
+// iouTracker 基于相邻帧IOU匹配的简单多目标跟踪器，为检测结果分配TrackID
+type iouTracker struct {
+	mu     sync.Mutex
+	nextID int
+	tracks []*trackedObject
+}
+
+// assign 将当前帧的检测框与已有轨迹按IOU从高到低贪心匹配（仅匹配同类别），
+// 匹配成功沿用轨迹的TrackID，未匹配的检测框开启新轨迹
+func (t *iouTracker) assign(detections []Detection) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	type candidate struct {
+		track, det int
+		iou        float32
+	}
+	var candidates []candidate
+	for ti, track := range t.tracks {
+		for di, det := range detections {
+			if det.ClassID != track.classID {
+				continue
+			}
+			if iou := boxIOU(track.box, det.Box); iou >= trackerIOUThreshold {
+				candidates = append(candidates, candidate{ti, di, iou})
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].iou > candidates[j].iou
+	})
+
+	trackMatched := make([]bool, len(t.tracks))
+	detMatched := make([]bool, len(detections))
+	for _, c := range candidates {
+		if trackMatched[c.track] || detMatched[c.det] {
+			continue
+		}
+		trackMatched[c.track] = true
+		detMatched[c.det] = true
+
+		track := t.tracks[c.track]
+		track.box = detections[c.det].Box
+		track.missed = 0
+		detections[c.det].TrackID = track.id
+	}
+
+	// 未匹配的轨迹累计丢失帧数，超过上限后移除
+	kept := t.tracks[:0]
+	for ti, track := range t.tracks {
+		if !trackMatched[ti] {
+			track.missed++
+			if track.missed > trackerMaxMissed {
+				continue
+			}
+		}
+		kept = append(kept, track)
+	}
+	t.tracks = kept
+
+	for di := range detections {
+		if detMatched[di] {
+			continue
+		}
+		t.nextID++
+		t.tracks = append(t.tracks, &trackedObject{
+			id:      t.nextID,
+			classID: detections[di].ClassID,
+			box:     detections[di].Box,
+		})
+		detections[di].TrackID = t.nextID
+	}
+}
@@ -0,0 +1,30 @@
+package yolo
+
+import "testing"
+
+// TestTrackerKeepsIDAcrossFrames 相邻帧中重叠的同类目标沿用TrackID，新目标分配新ID
+func TestTrackerKeepsIDAcrossFrames(t *testing.T) {
+	var tracker iouTracker
+
+	first := []Detection{
+		{ClassID: 0, Box: [4]float32{10, 10, 50, 50}},
+		{ClassID: 2, Box: [4]float32{200, 200, 260, 260}},
+	}
+	tracker.assign(first)
+	if first[0].TrackID == 0 || first[1].TrackID == 0 || first[0].TrackID == first[1].TrackID {
+		t.Fatalf("第一帧应分配不同的非零TrackID，实际 %d、%d", first[0].TrackID, first[1].TrackID)
+	}
+
+	second := []Detection{
+		{ClassID: 2, Box: [4]float32{205, 204, 265, 262}}, // 轻微移动
+		{ClassID: 0, Box: [4]float32{14, 12, 54, 52}},     // 轻微移动
+		{ClassID: 1, Box: [4]float32{12, 10, 52, 50}},     // 与目标0重叠但类别不同
+	}
+	tracker.assign(second)
+	if second[0].TrackID != first[1].TrackID || second[1].TrackID != first[0].TrackID {
+		t.Fatalf("移动后的目标应沿用TrackID，实际 %+v", second)
+	}
+	if id := second[2].TrackID; id == 0 || id == first[0].TrackID || id == first[1].TrackID {
+		t.Fatalf("不同类别的目标应分配新TrackID，实际 %d", id)
+	}
+}
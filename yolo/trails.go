@@ -0,0 +1,95 @@
+package yolo
+
+import (
+	"image"
+	"image/color"
+)
+
+// trackTrail 单个跟踪目标的轨迹
+type trackTrail struct {
+	points []image.Point // 最近的中心点（从旧到新）
+	missed int           // 连续未出现的帧数
+}
+
+// updateTrackTrails 用当前帧的检测结果更新轨迹，长时间未出现的目标会被移除
+func (y *YOLO) updateTrackTrails(detections []Detection, length int) {
+	y.trailsMu.Lock()
+	defer y.trailsMu.Unlock()
+
+	if y.trackTrails == nil {
+		y.trackTrails = make(map[int]*trackTrail)
+	}
+
+	seen := make(map[int]bool, len(detections))
+	for _, det := range detections {
+		if det.TrackID <= 0 {
+			continue
+		}
+		seen[det.TrackID] = true
+
+		trail := y.trackTrails[det.TrackID]
+		if trail == nil {
+			trail = &trackTrail{}
+			y.trackTrails[det.TrackID] = trail
+		}
+		center := image.Pt(int((det.Box[0]+det.Box[2])/2), int((det.Box[1]+det.Box[3])/2))
+		trail.points = append(trail.points, center)
+		if len(trail.points) > length {
+			trail.points = trail.points[len(trail.points)-length:]
+		}
+		trail.missed = 0
+	}
+
+	for id, trail := range y.trackTrails {
+		if seen[id] {
+			continue
+		}
+		trail.missed++
+		if trail.missed > length {
+			delete(y.trackTrails, id)
+		}
+	}
+}
+
+// drawTrackTrails 绘制当前帧中仍可见目标的轨迹折线
+func (y *YOLO) drawTrackTrails(img *image.RGBA, lineColor color.Color) {
+	y.trailsMu.Lock()
+	defer y.trailsMu.Unlock()
+
+	for _, trail := range y.trackTrails {
+		if trail.missed > 0 {
+			continue
+		}
+		for i := 1; i < len(trail.points); i++ {
+			drawLine(img, trail.points[i-1], trail.points[i], lineColor)
+		}
+	}
+}
+
+// drawLine 绘制两点之间的直线（超出图像的部分不绘制）
+func drawLine(img *image.RGBA, from, to image.Point, lineColor color.Color) {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	steps := absInt(dx)
+	if absInt(dy) > steps {
+		steps = absInt(dy)
+	}
+	if steps == 0 {
+		steps = 1
+	}
+
+	bounds := img.Bounds()
+	for i := 0; i <= steps; i++ {
+		p := image.Pt(from.X+dx*i/steps, from.Y+dy*i/steps)
+		if p.In(bounds) {
+			img.Set(p.X, p.Y, lineColor)
+		}
+	}
+}
+
+// absInt 整数绝对值
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
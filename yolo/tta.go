@@ -0,0 +1,40 @@
+package yolo
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/disintegration/imaging"
+)
+
+// applyTTA 测试时增强：对水平翻转的图像再推理一次，将结果翻转回原图坐标后与原结果合并
+// 未启用TTA时原样返回；合并使用NMS或WBF（取决于UseWBF），计算量约为2倍
+func (y *YOLO) applyTTA(img image.Image, detections []Detection) ([]Detection, error) {
+	if y.runtimeConfig == nil || !y.runtimeConfig.UseTTA {
+		return detections, nil
+	}
+
+	flipped := imaging.FlipH(img)
+	inputData, err := y.preprocessImageFromMemory(flipped)
+	if err != nil {
+		return nil, fmt.Errorf("TTA翻转图像预处理失败: %v", err)
+	}
+
+	flippedDetections, err := y.detectWithPreprocessedData(inputData, flipped, nil)
+	if err != nil {
+		return nil, fmt.Errorf("TTA翻转图像推理失败: %v", err)
+	}
+
+	// 相对原图宽度翻转回原坐标：x1' = W - x2, x2' = W - x1
+	width := float32(img.Bounds().Dx())
+	merged := make([]Detection, 0, len(detections)+len(flippedDetections))
+	merged = append(merged, detections...)
+	for _, det := range flippedDetections {
+		x1, x2 := det.Box[0], det.Box[2]
+		det.Box[0] = width - x2
+		det.Box[2] = width - x1
+		merged = append(merged, det)
+	}
+
+	return y.suppressOverlaps(merged), nil
+}
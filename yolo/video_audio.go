@@ -1,10 +1,12 @@
 package yolo
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,6 +18,9 @@ type AudioSaveOptions struct {
 	AudioBitrate  string  // 音频比特率 (默认: "128k")
 	TempDir       string  // 临时文件目录
 	Quality       float64 // 视频质量 (0.0-1.0)
+	CopyStreams   bool    // 直接复制已编码的视频流和原始音频流（-c:v copy -c:a copy），不重新编码
+
+	Video *VideoSaveOptions // 视频编码和封装格式，为nil时使用H.264
 }
 
 // DefaultAudioSaveOptions 返回默认的音频保存选项
@@ -29,12 +34,9 @@ func DefaultAudioSaveOptions() *AudioSaveOptions {
 	}
 }
 
-// SaveWithAudio 保存视频并保留音频
-func (dr *DetectionResults) SaveWithAudio(outputPath string) error {
-	if len(dr.Detections) == 0 {
-		return fmt.Errorf("没有检测结果可保存")
-	}
-
+// SaveWithAudio 保存视频并保留音频，可传入自定义选项（如CopyStreams、Video编码），不传时使用默认高质量设置
+// 没有任何检测结果的视频同样会保存
+func (dr *DetectionResults) SaveWithAudio(outputPath string, options ...*AudioSaveOptions) error {
 	if dr.InputPath == "" {
 		return fmt.Errorf("没有输入文件路径信息")
 	}
@@ -43,12 +45,17 @@ func (dr *DetectionResults) SaveWithAudio(outputPath string) error {
 		return fmt.Errorf("音频保存功能仅支持视频文件")
 	}
 
-	// 使用内置的默认高质量设置
-	opts := &AudioSaveOptions{
-		PreserveAudio: true,
-		AudioCodec:    "aac",
-		AudioBitrate:  "128k",
-		TempDir:       "", // 使用系统临时目录
+	// 未传入选项时使用内置的默认高质量设置，未填写的音频参数使用默认值
+	opts := DefaultAudioSaveOptions()
+	if len(options) > 0 && options[0] != nil {
+		custom := *options[0]
+		opts = &custom
+	}
+	if opts.AudioCodec == "" {
+		opts.AudioCodec = "aac"
+	}
+	if opts.AudioBitrate == "" {
+		opts.AudioBitrate = "128k"
 	}
 
 	// 检查FFmpeg是否可用
@@ -83,8 +90,8 @@ func (dr *DetectionResults) saveVideoWithAudioFromCache(outputPath string, opts
 	// 生成临时视频文件路径（无音频）
 	tempVideoPath := filepath.Join(tempDir, "temp_video_no_audio.mp4")
 
-	// 先保存无音频的视频
-	err := dr.saveVideoWithCachedResults(tempVideoPath)
+	// 先保存无音频的视频（流复制模式下直接按目标编码保存，合并时不再重新编码）
+	err := dr.saveVideoWithFFmpeg(tempVideoPath, opts.tempVideoOptions())
 	if err != nil {
 		return fmt.Errorf("保存临时视频失败: %v", err)
 	}
@@ -109,8 +116,13 @@ func (dr *DetectionResults) saveVideoWithAudioRedetect(outputPath string, opts *
 	// 生成临时视频文件路径（无音频）
 	tempVideoPath := filepath.Join(tempDir, "temp_video_no_audio.mp4")
 
-	// 重新检测并保存无音频视频
-	err := dr.detector.DetectVideoAndSave(dr.InputPath, tempVideoPath)
+	// 重新检测并保存无音频视频（流复制模式下按目标编码保存）
+	var err error
+	if videoOpts := opts.tempVideoOptions(); videoOpts != nil {
+		err = dr.SaveAnnotated(tempVideoPath, &AudioSaveOptions{Video: videoOpts})
+	} else {
+		err = dr.detector.DetectVideoAndSave(dr.InputPath, tempVideoPath)
+	}
 	if err != nil {
 		return fmt.Errorf("重新检测视频失败: %v", err)
 	}
@@ -119,25 +131,53 @@ func (dr *DetectionResults) saveVideoWithAudioRedetect(outputPath string, opts *
 	return dr.mergeAudioWithFFmpeg(dr.InputPath, tempVideoPath, outputPath, opts)
 }
 
+// tempVideoOptions 生成无音频临时视频时使用的编码选项
+// 流复制模式下合并时不再编码，临时视频需直接使用目标编码；否则临时视频使用默认H.264，合并时再按Video编码
+func (opts *AudioSaveOptions) tempVideoOptions() *VideoSaveOptions {
+	if opts.CopyStreams {
+		return opts.Video
+	}
+	return nil
+}
+
 // mergeAudioWithFFmpeg 使用FFmpeg合并音频和视频
 func (dr *DetectionResults) mergeAudioWithFFmpeg(originalVideoPath, processedVideoPath, outputPath string, opts *AudioSaveOptions) error {
 	fmt.Println("🔄 正在使用FFmpeg合并音频...")
 
 	// 构建FFmpeg命令 - 高质量编码设置
+	codecArgs, err := opts.Video.ffmpegArgs(outputPath)
+	if err != nil {
+		return err
+	}
+
 	args := []string{
 		"-i", processedVideoPath, // 处理后的视频（无音频）
 		"-i", originalVideoPath,  // 原始视频（有音频）
-		"-c:v", "libx264",        // 使用H.264编码器
-		"-crf", "18",            // CRF 18 视觉无损质量
-		"-preset", "slow",       // slow预设获得更好压缩
-		"-pix_fmt", "yuv420p",   // 使用yuv420p标准格式
+	}
+	args = append(args, codecArgs...)
+	args = append(args,
 		"-c:a", opts.AudioCodec,  // 音频编解码器
 		"-b:a", opts.AudioBitrate, // 音频比特率
 		"-map", "0:v:0",         // 使用第一个输入的视频流
-		"-map", "1:a:0",         // 使用第二个输入的音频流
+		"-map", "1:a:0?",        // 使用第二个输入的音频流（原视频没有音频时忽略）
 		"-shortest",             // 以最短流为准
 		"-y",                    // 覆盖输出文件
 		outputPath,
+	)
+
+	// 流复制模式：处理后的视频已按Video编码（见tempVideoOptions），直接封装视频和原始音频，避免二次编码
+	if opts.CopyStreams {
+		args = []string{
+			"-i", processedVideoPath,
+			"-i", originalVideoPath,
+			"-c:v", "copy",
+			"-c:a", "copy",
+			"-map", "0:v:0",
+			"-map", "1:a:0?",
+			"-shortest",
+			"-y",
+			outputPath,
+		}
 	}
 
 	// 执行FFmpeg命令
@@ -147,7 +187,7 @@ func (dr *DetectionResults) mergeAudioWithFFmpeg(originalVideoPath, processedVid
 	fmt.Printf("执行命令: ffmpeg %s\n", strings.Join(args, " "))
 
 	start := time.Now()
-	err := cmd.Run()
+	err = cmd.Run()
 	if err != nil {
 		return fmt.Errorf("FFmpeg合并音频失败: %v", err)
 	}
@@ -159,6 +199,130 @@ func (dr *DetectionResults) mergeAudioWithFFmpeg(originalVideoPath, processedVid
 	return nil
 }
 
+// SaveAnnotated 单次FFmpeg调用保存带检测框的视频并保留原始音频
+// 标注后的帧通过stdin管道直接送入FFmpeg，音频从源文件映射，无需临时文件和二次编码
+func (dr *DetectionResults) SaveAnnotated(outputPath string, opts *AudioSaveOptions) error {
+	if dr.InputPath == "" {
+		return fmt.Errorf("没有输入文件路径信息")
+	}
+
+	if !isVideoFile(dr.InputPath) {
+		return fmt.Errorf("SaveAnnotated 仅支持视频文件")
+	}
+
+	if !isFFmpegAvailable() {
+		return fmt.Errorf("FFmpeg未安装或不在PATH中")
+	}
+
+	if opts == nil {
+		opts = DefaultAudioSaveOptions()
+	}
+	codecArgs, err := opts.Video.ffmpegArgs(outputPath)
+	if err != nil {
+		return err
+	}
+	audioCodec := opts.AudioCodec
+	if audioCodec == "" {
+		audioCodec = "aac"
+	}
+	audioBitrate := opts.AudioBitrate
+	if audioBitrate == "" {
+		audioBitrate = "128k"
+	}
+
+	// 打开输入视频读取帧
+	video, err := dr.detector.openVideo(dr.InputPath)
+	if err != nil {
+		return fmt.Errorf("无法打开视频文件: %v", err)
+	}
+	defer video.Close()
+
+	width, height := video.Width(), video.Height()
+
+	// 输入0: 从stdin读取的RGBA原始帧；输入1: 源视频（仅取音频）
+	args := []string{
+		"-f", "rawvideo",
+		"-pix_fmt", "rgba",
+		"-s", fmt.Sprintf("%dx%d", width, height),
+		"-r", fmt.Sprintf("%.02f", video.FPS()),
+		"-i", "-",
+	}
+	if opts.PreserveAudio {
+		args = append(args, "-i", dr.InputPath)
+	}
+	args = append(args,
+		"-map", "0:v:0",
+	)
+	args = append(args, codecArgs...)
+	if opts.PreserveAudio {
+		args = append(args,
+			"-map", "1:a:0?", // 源视频有音频时才映射
+			"-c:a", audioCodec,
+			"-b:a", audioBitrate,
+			"-shortest",
+		)
+	}
+	args = append(args, "-y", outputPath)
+
+	cmd := exec.Command("ffmpeg", args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("创建FFmpeg输入管道失败: %v", err)
+	}
+
+	fmt.Printf("🎬 单次FFmpeg保存带检测框视频: ffmpeg %s\n", strings.Join(args, " "))
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("启动FFmpeg失败: %v", err)
+	}
+
+	frameCount := 0
+	resultsByFrame := dr.detectionsByFrame()
+	clock := dr.detector.newFrameClock(dr.InputPath, video.FPS())
+	var writeErr error
+	for video.Read() {
+		frameCount++
+
+		frameImg := convertFrameBufferToImage(video.FrameBuffer(), width, height)
+
+		// 优先使用缓存的检测结果，没有缓存时逐帧检测
+		var detections []Detection
+		if len(dr.VideoResults) > 0 {
+			detections = resultsByFrame[frameCount]
+		} else {
+			detections, err = dr.detector.detectImage(frameImg)
+			if err != nil {
+				detections = nil
+			}
+		}
+
+		timestamp := clock.At(frameCount)
+		resultImg := dr.detector.annotateFrame(frameImg, detections, timestamp)
+
+		if _, writeErr = stdin.Write(convertImageToFrameBuffer(resultImg)); writeErr != nil {
+			break
+		}
+
+		if frameCount%30 == 0 {
+			dr.detector.verbosef("📊 已处理 %d 帧...\n", frameCount)
+		}
+	}
+
+	stdin.Close()
+	waitErr := cmd.Wait()
+	if writeErr != nil {
+		return fmt.Errorf("写入帧到FFmpeg失败: %v", writeErr)
+	}
+	if waitErr != nil {
+		return fmt.Errorf("FFmpeg保存视频失败: %v", waitErr)
+	}
+
+	fmt.Printf("✅ 视频保存完成！共处理 %d 帧，耗时: %.2f秒\n", frameCount, time.Since(start).Seconds())
+	fmt.Printf("📁 输出文件: %s\n", outputPath)
+	return nil
+}
+
 // isFFmpegAvailable 检查FFmpeg是否可用
 func isFFmpegAvailable() bool {
 	cmd := exec.Command("ffmpeg", "-version")
@@ -207,19 +371,116 @@ func GetVideoInfo(videoPath string) (*VideoInfo, error) {
 		return nil, fmt.Errorf("获取视频信息失败: %v", err)
 	}
 
-	// 这里可以解析JSON输出，暂时返回基本信息
-	return &VideoInfo{
-		Path:     videoPath,
-		HasAudio: strings.Contains(string(output), "\"codec_type\": \"audio\""),
-		RawInfo:  string(output),
-	}, nil
+	info, err := parseFFprobeOutput(output)
+	if err != nil {
+		return nil, fmt.Errorf("解析视频信息失败: %v", err)
+	}
+	info.Path = videoPath
+	return info, nil
 }
 
 // VideoInfo 视频信息
 type VideoInfo struct {
-	Path     string // 视频路径
-	HasAudio bool   // 是否包含音频
-	RawInfo  string // 原始信息（JSON格式）
+	Path     string        // 视频路径
+	Width    int           // 视频宽度
+	Height   int           // 视频高度
+	Duration time.Duration // 视频时长
+	FPS      float64       // 帧率
+	Codec    string        // 视频编码
+	Bitrate  int64         // 总码率（bit/s）
+
+	HasAudio        bool   // 是否包含音频
+	AudioCodec      string // 音频编码
+	AudioSampleRate int    // 音频采样率（Hz）
+	AudioChannels   int    // 音频声道数
+	AudioBitrate    int64  // 音频码率（bit/s）
+
+	RawInfo string // 原始信息（JSON格式）
+}
+
+// ffprobeOutput ffprobe -print_format json 的输出结构
+type ffprobeOutput struct {
+	Streams []struct {
+		CodecType    string `json:"codec_type"`
+		CodecName    string `json:"codec_name"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		RFrameRate   string `json:"r_frame_rate"`
+		AvgFrameRate string `json:"avg_frame_rate"`
+		Duration     string `json:"duration"`
+		BitRate      string `json:"bit_rate"`
+		SampleRate   string `json:"sample_rate"`
+		Channels     int    `json:"channels"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+		BitRate  string `json:"bit_rate"`
+	} `json:"format"`
+}
+
+// parseFFprobeOutput 将ffprobe的JSON输出解析为VideoInfo（取第一条视频流和第一条音频流）
+func parseFFprobeOutput(output []byte) (*VideoInfo, error) {
+	var probe ffprobeOutput
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return nil, err
+	}
+
+	info := &VideoInfo{RawInfo: string(output)}
+	durationSec, _ := strconv.ParseFloat(probe.Format.Duration, 64)
+	info.Bitrate, _ = strconv.ParseInt(probe.Format.BitRate, 10, 64)
+
+	hasVideo := false
+	for _, stream := range probe.Streams {
+		switch stream.CodecType {
+		case "video":
+			if hasVideo {
+				continue
+			}
+			hasVideo = true
+			info.Width = stream.Width
+			info.Height = stream.Height
+			info.Codec = stream.CodecName
+			info.FPS = parseFrameRate(stream.AvgFrameRate)
+			if info.FPS == 0 {
+				info.FPS = parseFrameRate(stream.RFrameRate)
+			}
+			if durationSec == 0 {
+				durationSec, _ = strconv.ParseFloat(stream.Duration, 64)
+			}
+			if info.Bitrate == 0 {
+				info.Bitrate, _ = strconv.ParseInt(stream.BitRate, 10, 64)
+			}
+		case "audio":
+			if info.HasAudio {
+				continue
+			}
+			info.HasAudio = true
+			info.AudioCodec = stream.CodecName
+			info.AudioSampleRate, _ = strconv.Atoi(stream.SampleRate)
+			info.AudioChannels = stream.Channels
+			info.AudioBitrate, _ = strconv.ParseInt(stream.BitRate, 10, 64)
+		}
+	}
+	info.Duration = time.Duration(durationSec * float64(time.Second))
+
+	return info, nil
+}
+
+// parseFrameRate 解析ffprobe的分数帧率（如 "30000/1001"）
+func parseFrameRate(rate string) float64 {
+	parts := strings.SplitN(rate, "/", 2)
+	num, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0
+	}
+	if len(parts) == 1 {
+		return num
+	}
+	den, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || den == 0 {
+		return 0
+	}
+	return num / den
 }
 
 // HasAudioTrack 检查视频是否包含音频轨道
@@ -0,0 +1,83 @@
+package yolo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// VideoBatchResult 批量视频检测中单个文件的结果
+type VideoBatchResult struct {
+	Path    string
+	Results *DetectionResults // 检测失败时为nil
+	Err     error
+}
+
+// DetectVideosBatch 并发检测多个视频文件，每个文件使用独立的帧读取器，返回结果与paths顺序一致
+// 检测器非并发安全，因此除当前检测器外为每个并发工作线程额外加载一个同配置的检测器（独立会话），
+// 全部文件处理完后关闭；concurrency<=0 或大于文件数时按文件数计算
+func (y *YOLO) DetectVideosBatch(paths []string, options *DetectionOptions, concurrency int) ([]VideoBatchResult, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	if concurrency <= 0 || concurrency > len(paths) {
+		concurrency = len(paths)
+	}
+
+	// 第一个工作线程复用当前检测器，其余各自创建检测器
+	workers := []*YOLO{y}
+	for len(workers) < concurrency {
+		cfg := *y.config
+		detector, err := NewYOLO(y.modelPath, y.classPath, &cfg)
+		if err != nil {
+			for _, w := range workers[1:] {
+				w.Close()
+			}
+			return nil, fmt.Errorf("创建工作检测器失败: %w", err)
+		}
+		workers = append(workers, detector)
+	}
+	defer func() {
+		for _, w := range workers[1:] {
+			w.Close()
+		}
+	}()
+
+	fmt.Printf("🎬 批量检测 %d 个视频文件，并发数 %d\n", len(paths), concurrency)
+	start := time.Now()
+
+	results := make([]VideoBatchResult, len(paths))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for _, worker := range workers {
+		wg.Add(1)
+		go func(detector *YOLO) {
+			defer wg.Done()
+			for i := range jobs {
+				res, err := detector.Detect(paths[i], options)
+				if err == nil && res != nil {
+					// 工作检测器在返回前关闭，结果改为关联到当前检测器（同模型同配置）
+					res.detector = y
+				}
+				results[i] = VideoBatchResult{Path: paths[i], Results: res, Err: err}
+			}
+		}(worker)
+	}
+
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Printf("❌ 视频检测失败 %s: %v\n", r.Path, r.Err)
+		}
+	}
+	fmt.Printf("✅ 批量视频检测完成: 成功 %d, 失败 %d, 耗时 %v\n", len(paths)-failed, failed, time.Since(start))
+
+	return results, nil
+}
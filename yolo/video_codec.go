@@ -0,0 +1,166 @@
+package yolo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// VideoCodec 保存视频时使用的编码格式
+type VideoCodec string
+
+const (
+	VideoCodecH264 VideoCodec = "h264" // H.264/AVC（默认，兼容性最好）
+	VideoCodecH265 VideoCodec = "hevc" // H.265/HEVC，同画质下文件更小
+	VideoCodecVP9  VideoCodec = "vp9"  // VP9，适合WebM/浏览器播放
+	VideoCodecAV1  VideoCodec = "av1"  // AV1，压缩率最高但编码最慢
+)
+
+// VideoSaveOptions 视频编码和封装格式选项，零值字段使用对应编码器的默认值
+type VideoSaveOptions struct {
+	Codec     VideoCodec // 视频编码，为空时使用H.264
+	CRF       int        // 恒定质量参数（越小画质越高），<=0 时使用编码器默认值
+	Preset    string     // 编码速度预设（H.264/H.265为slow等，AV1为0-13），为空时使用默认值
+	Container string     // 封装格式（mp4、mkv、webm、mov），为空时根据输出文件扩展名推断
+}
+
+// videoCodecSpec 编码格式对应的FFmpeg编码器及默认参数
+type videoCodecSpec struct {
+	encoder    string
+	defaultCRF int
+	preset     string
+	extraArgs  []string
+	containers []string // 支持的封装格式
+}
+
+// videoCodecSpecs 支持的编码格式
+var videoCodecSpecs = map[VideoCodec]videoCodecSpec{
+	VideoCodecH264: {
+		encoder:    "libx264",
+		defaultCRF: 18,
+		preset:     "slow",
+		containers: []string{"mp4", "mkv", "mov"},
+	},
+	VideoCodecH265: {
+		encoder:    "libx265",
+		defaultCRF: 22,
+		preset:     "slow",
+		extraArgs:  []string{"-tag:v", "hvc1"}, // Apple播放器需要hvc1标签
+		containers: []string{"mp4", "mkv", "mov"},
+	},
+	VideoCodecVP9: {
+		encoder:    "libvpx-vp9",
+		defaultCRF: 31,
+		extraArgs:  []string{"-b:v", "0", "-row-mt", "1"}, // -b:v 0 启用恒定质量模式
+		containers: []string{"webm", "mkv", "mp4"},
+	},
+	VideoCodecAV1: {
+		encoder:    "libsvtav1",
+		defaultCRF: 35,
+		preset:     "8",
+		containers: []string{"mp4", "mkv", "webm"},
+	},
+}
+
+// knownVideoContainers 会做编码兼容性校验的封装格式
+var knownVideoContainers = []string{"mp4", "mkv", "webm", "mov"}
+
+// DefaultVideoSaveOptions 返回默认的视频保存选项（H.264, CRF 18, slow）
+func DefaultVideoSaveOptions() *VideoSaveOptions {
+	return &VideoSaveOptions{
+		Codec:  VideoCodecH264,
+		CRF:    18,
+		Preset: "slow",
+	}
+}
+
+// ffmpegArgs 生成视频编码和封装相关的FFmpeg参数，opts为nil时使用默认值
+func (opts *VideoSaveOptions) ffmpegArgs(outputPath string) ([]string, error) {
+	if opts == nil {
+		opts = DefaultVideoSaveOptions()
+	}
+
+	codec := VideoCodec(strings.ToLower(string(opts.Codec)))
+	switch codec {
+	case "":
+		codec = VideoCodecH264
+	case "h265", "x265":
+		codec = VideoCodecH265
+	}
+	spec, ok := videoCodecSpecs[codec]
+	if !ok {
+		return nil, fmt.Errorf("%w: 不支持的视频编码 %q（可选 h264、hevc、vp9、av1）", ErrUnsupportedFormat, opts.Codec)
+	}
+
+	container := strings.ToLower(strings.TrimPrefix(opts.Container, "."))
+	if container == "" {
+		container = strings.ToLower(strings.TrimPrefix(filepath.Ext(outputPath), "."))
+	}
+	// 仅校验已知封装格式，其他扩展名交给FFmpeg自行处理
+	if containsString(knownVideoContainers, container) && !containsString(spec.containers, container) {
+		return nil, fmt.Errorf("%w: %s 编码不能封装为 %s（可选 %s）", ErrUnsupportedFormat,
+			codec, container, strings.Join(spec.containers, "、"))
+	}
+
+	crf := opts.CRF
+	if crf <= 0 {
+		crf = spec.defaultCRF
+	}
+	preset := opts.Preset
+	if preset == "" {
+		preset = spec.preset
+	}
+
+	args := []string{"-c:v", spec.encoder, "-crf", strconv.Itoa(crf)}
+	if preset != "" {
+		args = append(args, "-preset", preset)
+	}
+	args = append(args, spec.extraArgs...)
+	args = append(args, "-pix_fmt", "yuv420p")
+
+	// 显式指定封装格式时输出文件扩展名可以不同
+	if opts.Container != "" {
+		format := container
+		if format == "mkv" {
+			format = "matroska"
+		}
+		args = append(args, "-f", format)
+	}
+	return args, nil
+}
+
+// containsString 判断切片中是否包含指定字符串
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveWithOptions 按指定的编码和封装格式保存带检测框的视频（不保留音频）
+// 始终通过FFmpeg编码，有缓存检测结果时直接使用，否则逐帧重新检测
+func (dr *DetectionResults) SaveWithOptions(outputPath string, opts *VideoSaveOptions) error {
+	if dr.InputPath == "" {
+		return fmt.Errorf("没有输入文件路径信息")
+	}
+	if !isVideoFile(dr.InputPath) {
+		return fmt.Errorf("SaveWithOptions 仅支持视频文件")
+	}
+	if !isFFmpegAvailable() {
+		return fmt.Errorf("FFmpeg未安装或不在PATH中，无法使用指定编码保存视频")
+	}
+	if _, err := opts.ffmpegArgs(outputPath); err != nil {
+		return err
+	}
+
+	if len(dr.VideoResults) > 0 {
+		fmt.Println("🚀 使用已有检测结果保存视频...")
+		return dr.saveVideoWithFFmpeg(outputPath, opts)
+	}
+
+	fmt.Println("⚠️ 没有缓存的检测结果，将重新检测视频...")
+	return dr.SaveAnnotated(outputPath, &AudioSaveOptions{Video: opts})
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"image"
-	"image/color"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -44,13 +43,24 @@ type VideoOptimization struct {
 	cancel          context.CancelFunc
 	isShutdown      int64 // atomic
 
+	// 结果队列背压：队列满时最多等待resultSendTimeout，超时后丢弃并计数
+	resultSendTimeout time.Duration
+	droppedResults    int64 // atomic
+
 	// 垃圾回收优化字段
 	frameCounter    int64 // 帧计数器，用于定期垃圾回收
 	gcInterval      int64 // GC间隔，默认每20-50帧清理一次
 	lastGCTime      time.Time // 上次GC时间
 	gcMutex         sync.Mutex // GC操作互斥锁
+
+	// 预处理缩放插值算法（与检测器配置保持一致）
+	resizeFilter ResizeFilter
+	maxDimension int // 预处理前的最长边上限（0表示不限制）
 }
 
+// DefaultResultSendTimeout 结果队列满时asyncWorker等待调用方取走结果的默认时长
+const DefaultResultSendTimeout = time.Second
+
 // ProcessTask 异步处理任务
 type ProcessTask struct {
 	img    image.Image
@@ -76,6 +86,9 @@ type CircuitBreaker struct {
 	maxFailures   int64
 	timeout       time.Duration
 	retryTimeout  time.Duration
+
+	onOpen  func() // 熔断器打开时的回调
+	onClose func() // 熔断器恢复时的回调
 }
 
 type CircuitState int
@@ -138,8 +151,22 @@ func NewVideoOptimization(enableGPU bool) *VideoOptimization {
 
 // NewVideoOptimizationWithCUDA 创建带CUDA加速的视频优化实例
 func NewVideoOptimizationWithCUDA(enableGPU, enableCUDA bool, cudaDeviceID int) *VideoOptimization {
+	return NewVideoOptimizationWithOptions(enableGPU, enableCUDA, cudaDeviceID, ParallelismOptions{})
+}
+
+// ParallelismOptions 优化实例的并行度设置，字段为0时按CPU核数自动计算
+// 容器中CPU限额通常小于宿主机核数，显式设置可避免创建过多工作协程
+type ParallelismOptions struct {
+	Workers      int // 并行工作线程数
+	BatchSize    int // 批处理大小
+	MaxBatchSize int // 最大批处理大小（0时为BatchSize的2倍）
+	QueueSize    int // 异步任务队列和结果队列容量（0时为MaxBatchSize的2倍）
+}
+
+// NewVideoOptimizationWithOptions 使用指定并行度创建视频优化实例
+func NewVideoOptimizationWithOptions(enableGPU, enableCUDA bool, cudaDeviceID int, opts ParallelismOptions) *VideoOptimization {
 	// 平衡性能与内存使用
-	cpuCores := runtime.NumCPU()
+	cpuCores := EffectiveCPUs()
 
 	// 合理的批处理大小，避免内存不足
 	batchSize := cpuCores * 2       // 基础批处理
@@ -160,6 +187,25 @@ func NewVideoOptimizationWithCUDA(enableGPU, enableCUDA bool, cudaDeviceID int)
 		parallelWorkers = cpuCores * 4 // CUDA并行工作线程
 	}
 
+	// 显式设置的并行度优先于自动计算
+	if opts.Workers > 0 {
+		parallelWorkers = opts.Workers
+	}
+	if opts.BatchSize > 0 {
+		batchSize = opts.BatchSize
+		maxBatchSize = batchSize * 2
+	}
+	if opts.MaxBatchSize > 0 {
+		maxBatchSize = opts.MaxBatchSize
+	}
+	if maxBatchSize < batchSize {
+		maxBatchSize = batchSize
+	}
+	queueSize := maxBatchSize * 2
+	if opts.QueueSize > 0 {
+		queueSize = opts.QueueSize
+	}
+
 	// 预分配合理的内存缓冲区
 	preprocessBuf := make([][]float32, batchSize)
 	memoryBuffer := make([][]float32, maxBatchSize)
@@ -187,8 +233,8 @@ func NewVideoOptimizationWithCUDA(enableGPU, enableCUDA bool, cudaDeviceID int)
 	}
 
 	// 创建异步处理队列
-	asyncQueue := make(chan *ProcessTask, maxBatchSize*2)
-	processDone := make(chan *ProcessResult, maxBatchSize*2)
+	asyncQueue := make(chan *ProcessTask, queueSize)
+	processDone := make(chan *ProcessResult, queueSize)
 	workerPool := make(chan struct{}, parallelWorkers)
 
 	// 填充工作池
@@ -266,6 +312,7 @@ func NewVideoOptimizationWithCUDA(enableGPU, enableCUDA bool, cudaDeviceID int)
 		ctx:             ctx,
 		cancel:          cancel,
 		isShutdown:      0,
+		resultSendTimeout: DefaultResultSendTimeout,
 		// 垃圾回收优化字段
 		frameCounter:    0,
 		gcInterval:      30, // 默认每30帧清理一次，平衡性能与内存
@@ -363,14 +410,8 @@ func (vo *VideoOptimization) asyncWorker() {
 			// 先释放工作许可，避免死锁
 			vo.workerPool <- struct{}{}
 
-			// 非阻塞发送结果，避免死锁
-			select {
-			case vo.processDone <- result:
-				// 成功发送结果
-			default:
-				// 结果通道满时丢弃结果，避免死锁
-				// 在实际应用中可以考虑记录日志或其他处理方式
-			}
+			// 有界阻塞发送：结果队列满时等待调用方取走，超时后丢弃并计数，避免工作线程永久阻塞
+			vo.sendResult(result)
 
 		case <-vo.ctx.Done():
 			// 上下文取消，退出工作线程
@@ -379,16 +420,55 @@ func (vo *VideoOptimization) asyncWorker() {
 	}
 }
 
+// sendResult 将结果放入结果队列，队列满时最多等待resultSendTimeout（<=0表示不等待）
+func (vo *VideoOptimization) sendResult(result *ProcessResult) {
+	select {
+	case vo.processDone <- result:
+		return
+	default:
+	}
+
+	if vo.resultSendTimeout > 0 {
+		timer := time.NewTimer(vo.resultSendTimeout)
+		defer timer.Stop()
+		select {
+		case vo.processDone <- result:
+			return
+		case <-timer.C:
+		case <-vo.ctx.Done():
+		}
+	}
+
+	if dropped := atomic.AddInt64(&vo.droppedResults, 1); dropped == 1 || dropped%100 == 0 {
+		fmt.Printf("⚠️  结果队列已满，已丢弃 %d 个处理结果（任务ID %d），请及时调用GetAsyncResult取走结果\n", dropped, result.id)
+	}
+}
+
+// DroppedResults 返回因结果队列已满而丢弃的处理结果数量
+func (vo *VideoOptimization) DroppedResults() int64 {
+	return atomic.LoadInt64(&vo.droppedResults)
+}
+
+// SetResultSendTimeout 设置结果队列满时的最长等待时间（<=0表示不等待，队列满时立即丢弃）
+func (vo *VideoOptimization) SetResultSendTimeout(timeout time.Duration) {
+	vo.resultSendTimeout = timeout
+}
+
 // 熔断器相关方法
 func (vo *VideoOptimization) circuitBreakerAllow() bool {
-	vo.circuitBreaker.mu.RLock()
-	defer vo.circuitBreaker.mu.RUnlock()
+	vo.circuitBreaker.mu.Lock()
+	defer vo.circuitBreaker.mu.Unlock()
 
 	switch vo.circuitBreaker.state {
 	case Closed:
 		return true
 	case Open:
-		return time.Now().After(vo.circuitBreaker.nextRetryTime)
+		// 重试时间到达后进入半开状态，放行试探请求
+		if !time.Now().After(vo.circuitBreaker.nextRetryTime) {
+			return false
+		}
+		vo.circuitBreaker.state = HalfOpen
+		return true
 	case HalfOpen:
 		return true
 	default:
@@ -398,22 +478,34 @@ func (vo *VideoOptimization) circuitBreakerAllow() bool {
 
 func (vo *VideoOptimization) circuitBreakerRecord(success bool) {
 	vo.circuitBreaker.mu.Lock()
-	defer vo.circuitBreaker.mu.Unlock()
 
+	// 记录状态变化，解锁后再通知回调，避免回调中调用熔断器方法时死锁
+	var notify func()
 	if success {
+		// 只有半开状态下的试探请求成功才恢复，熔断前发出的请求晚到的成功不影响熔断
 		if vo.circuitBreaker.state == HalfOpen {
 			vo.circuitBreaker.state = Closed
 			vo.circuitBreaker.failureCount = 0
+			notify = vo.circuitBreaker.onClose
 		}
 	} else {
 		vo.circuitBreaker.failureCount++
 		vo.circuitBreaker.lastFailTime = time.Now()
 
-		if vo.circuitBreaker.failureCount >= vo.circuitBreaker.maxFailures {
+		// 半开状态下试探失败立即重新熔断
+		if vo.circuitBreaker.state == HalfOpen || vo.circuitBreaker.failureCount >= vo.circuitBreaker.maxFailures {
+			if vo.circuitBreaker.state != Open {
+				notify = vo.circuitBreaker.onOpen
+			}
 			vo.circuitBreaker.state = Open
 			vo.circuitBreaker.nextRetryTime = time.Now().Add(vo.circuitBreaker.retryTimeout)
 		}
 	}
+	vo.circuitBreaker.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
 }
 
 // 限流器相关方法
@@ -637,9 +729,8 @@ func (vo *VideoOptimization) extremePreprocessImage(img image.Image, inputWidth,
 
 // fastResize 快速图像缩放 - 修复坐标转换问题
 func (vo *VideoOptimization) fastResize(img image.Image, width, height int) image.Image {
-	// 修复：使用与CPU路径相同的缩放算法，确保坐标转换一致性
-	// 从 imaging.NearestNeighbor 改为 imaging.Lanczos
-	return imaging.Resize(img, width, height, imaging.Lanczos)
+	// 使用与CPU路径相同的缩放算法（由YOLOConfig.ResizeFilter决定），确保坐标转换一致性
+	return imaging.Resize(limitDimension(img, vo.maxDimension), width, height, vo.resizeFilter.resample())
 }
 
 // extremeFastResize 极致性能图像缩放 - 修复坐标转换问题
@@ -650,44 +741,8 @@ func (vo *VideoOptimization) extremeFastResize(img image.Image, width, height in
 		return img // 无需缩放，直接返回
 	}
 
-	// 修复：使用与CPU路径相同的缩放算法，确保坐标转换一致性
-	// 从 imaging.NearestNeighbor 改为 imaging.Lanczos
-	return imaging.Resize(img, width, height, imaging.Lanczos)
-}
-
-// resizeWithPadding 保持宽高比的缩放和填充 - 修复数据类型一致性
-func (vo *VideoOptimization) resizeWithPadding(img image.Image, targetWidth, targetHeight int) image.Image {
-	bounds := img.Bounds()
-	origWidth := bounds.Dx()
-	origHeight := bounds.Dy()
-
-	// 修复：使用与yolo.go中相同的数据类型，确保坐标转换一致性
-	// 计算缩放比例，保持宽高比
-	scaleX := float32(targetWidth) / float32(origWidth)
-	scaleY := float32(targetHeight) / float32(origHeight)
-	scale := scaleX
-	if scaleY < scaleX {
-		scale = scaleY
-	}
-
-	// 计算缩放后的尺寸
-	newWidth := int(float32(origWidth) * scale)
-	newHeight := int(float32(origHeight) * scale)
-
-	// 缩放图像（修复：使用与CPU路径相同的缩放算法）
-	resized := imaging.Resize(img, newWidth, newHeight, imaging.Lanczos)
-
-	// 创建目标尺寸的黑色背景
-	result := imaging.New(targetWidth, targetHeight, color.NRGBA{0, 0, 0, 255})
-
-	// 计算居中位置
-	offsetX := (targetWidth - newWidth) / 2
-	offsetY := (targetHeight - newHeight) / 2
-
-	// 将缩放后的图像粘贴到中心
-	result = imaging.Paste(result, resized, image.Pt(offsetX, offsetY))
-
-	return result
+	// 使用与CPU路径相同的缩放算法（由YOLOConfig.ResizeFilter决定），确保坐标转换一致性
+	return imaging.Resize(limitDimension(img, vo.maxDimension), width, height, vo.resizeFilter.resample())
 }
 
 // fastNormalize 快速归一化（通用版本）
@@ -909,8 +964,13 @@ func (vo *VideoOptimization) GetPreprocessBuffer() [][]float32 {
 	return vo.preprocessBuf
 }
 
-// OptimizedDetectImage 优化的图像检测方法
+// OptimizedDetectImage 优化的图像检测方法（可并发调用，不记录检测器的单帧耗时）
 func (vo *VideoOptimization) OptimizedDetectImage(detector *YOLO, img image.Image) ([]Detection, error) {
+	return vo.optimizedDetectImage(detector, img, nil)
+}
+
+// optimizedDetectImage 优化的图像检测，预处理和推理耗时累加到timings（可为nil）
+func (vo *VideoOptimization) optimizedDetectImage(detector *YOLO, img image.Image, timings *Timings) ([]Detection, error) {
 	// 获取输入尺寸
 	inputWidth := detector.config.InputWidth
 	inputHeight := detector.config.InputHeight
@@ -922,13 +982,15 @@ func (vo *VideoOptimization) OptimizedDetectImage(detector *YOLO, img image.Imag
 	}
 
 	// 使用极致性能预处理
+	preStart := time.Now()
 	data, err := vo.extremePreprocessImage(img, inputWidth, inputHeight)
 	if err != nil {
 		return nil, fmt.Errorf("预处理失败: %v", err)
 	}
+	timings.addPreprocess(time.Since(preStart))
 
 	// 调用检测器的内部方法，跳过重复预处理
-	result, err := detector.detectWithPreprocessedData(data, img)
+	result, err := detector.detectWithPreprocessedData(data, img, timings)
 	
 	// 智能垃圾回收 - 安全地清理临时内存
 	vo.SmartGarbageCollect(false)
@@ -961,6 +1023,26 @@ func (vo *VideoOptimization) BatchDetectImages(detector *YOLO, images []image.Im
 		batchSize = len(images)
 	}
 
+	// 动态批次模型：每批合并为一个输入张量推理
+	if detector.SupportsDynamicBatch() && detector.config.Anchors == nil {
+		results := make([][]Detection, 0, len(images))
+		for i := 0; i < len(images); i += batchSize {
+			end := i + batchSize
+			if end > len(images) {
+				end = len(images)
+			}
+			batch, err := detector.DetectBatch(images[i:end])
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, batch...)
+		}
+		vo.SmartGarbageCollect(len(images) >= 20)
+		return results, nil
+	}
+
+	// 固定批次模型：逐张推理
+
 	results := make([][]Detection, len(images))
 	var wg sync.WaitGroup
 	var mu sync.Mutex // 保护results切片的并发写入
@@ -980,6 +1062,10 @@ func (vo *VideoOptimization) BatchDetectImages(detector *YOLO, images []image.Im
 			defer wg.Done()
 			for j := start; j < end; j++ {
 				detections, err := vo.OptimizedDetectImage(detector, images[j])
+				if err == nil {
+					// 与DetectBatch一致，统一执行TTA、类别映射等后处理
+					detections, err = detector.finalizeDetections(images[j], detections)
+				}
 				if err != nil {
 					// 只记录第一个错误，避免通道阻塞
 					errorOnce.Do(func() {
@@ -1127,6 +1213,7 @@ func (vo *VideoOptimization) GetStabilityStatus() map[string]interface{} {
 		"max_latency":      vo.metrics.maxLatency,
 		"min_latency":      vo.metrics.minLatency,
 		"throughput":       vo.metrics.throughput,
+		"dropped_results":  vo.DroppedResults(),
 	}
 	vo.metrics.mu.RUnlock()
 
@@ -1152,6 +1239,7 @@ func (vo *VideoOptimization) ResetStabilityMetrics() {
 	vo.metrics.throughput = 0
 	vo.metrics.lastUpdate = time.Now()
 	vo.metrics.mu.Unlock()
+	atomic.StoreInt64(&vo.droppedResults, 0)
 
 	// 重置健康检查
 	vo.healthChecker.mu.Lock()
@@ -1181,6 +1269,21 @@ func (vo *VideoOptimization) SetRateLimitSettings(maxTokens, refillRate int64) {
 	vo.rateLimiter.tokens = maxTokens // 立即生效
 }
 
+// OnCircuitOpen 设置熔断器因连续失败而打开时的回调（在记录失败的协程中同步调用），
+// 应用可据此告警或降低输入速率；熔断期间的任务会返回 "circuit breaker open" 错误
+func (vo *VideoOptimization) OnCircuitOpen(fn func()) {
+	vo.circuitBreaker.mu.Lock()
+	defer vo.circuitBreaker.mu.Unlock()
+	vo.circuitBreaker.onOpen = fn
+}
+
+// OnCircuitClose 设置熔断器恢复（重试成功）时的回调
+func (vo *VideoOptimization) OnCircuitClose(fn func()) {
+	vo.circuitBreaker.mu.Lock()
+	defer vo.circuitBreaker.mu.Unlock()
+	vo.circuitBreaker.onClose = fn
+}
+
 // SetCircuitBreakerSettings 动态调整熔断器设置 - 疯狂调用保护
 func (vo *VideoOptimization) SetCircuitBreakerSettings(maxFailures int64, timeout, retryTimeout time.Duration) {
 	vo.circuitBreaker.mu.Lock()
@@ -1298,6 +1401,16 @@ func (vo *VideoOptimization) SmartGarbageCollect(forceGC bool) {
 	}
 }
 
+// SetResizeFilter 设置预处理缩放插值算法
+func (vo *VideoOptimization) SetResizeFilter(filter ResizeFilter) {
+	vo.resizeFilter = filter
+}
+
+// SetMaxPreprocessDimension 设置预处理前的最长边上限（0表示不限制）
+func (vo *VideoOptimization) SetMaxPreprocessDimension(px int) {
+	vo.maxDimension = px
+}
+
 // SetGCInterval 设置垃圾回收间隔
 func (vo *VideoOptimization) SetGCInterval(interval int64) {
 	vo.gcMutex.Lock()
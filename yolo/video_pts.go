@@ -0,0 +1,87 @@
+package yolo
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// frameClock 计算视频帧的时间戳
+// Vidio不提供逐帧PTS，默认按 帧号/FPS 计算，可变帧率（VFR）视频会有偏差；
+// 启用YOLOConfig.FramePTS时通过ffprobe读取每帧的实际PTS
+type frameClock struct {
+	fps        float64
+	timestamps []time.Duration // 按显示顺序排列的帧时间戳（相对第一帧），为空时按帧率计算
+}
+
+// newFrameClock 创建视频的帧时间戳计算器，读取PTS失败时回退到按帧率计算
+func (y *YOLO) newFrameClock(path string, fps float64) frameClock {
+	clock := frameClock{fps: fps}
+	if !y.config.FramePTS {
+		return clock
+	}
+
+	timestamps, err := probeFrameTimestamps(path)
+	if err != nil {
+		fmt.Printf("⚠️  读取帧时间戳失败，按帧率计算: %v\n", err)
+		return clock
+	}
+	clock.timestamps = timestamps
+	return clock
+}
+
+// At 返回第frameNumber帧（从1开始）的时间戳
+func (c frameClock) At(frameNumber int) time.Duration {
+	if frameNumber >= 1 && frameNumber <= len(c.timestamps) {
+		return c.timestamps[frameNumber-1]
+	}
+	// 与PTS一致以第一帧为0，帧号从1开始
+	if c.fps <= 0 || frameNumber < 1 {
+		return 0
+	}
+	return time.Duration(float64(frameNumber-1)/c.fps*1000) * time.Millisecond
+}
+
+// probeFrameTimestamps 使用ffprobe读取视频流所有数据包的PTS（无需解码），按显示顺序排序并以第一帧为0
+func probeFrameTimestamps(path string) ([]time.Duration, error) {
+	cmd := exec.Command("ffprobe", "-v", "error", "-select_streams", "v:0",
+		"-show_entries", "packet=pts_time", "-of", "csv=p=0", path)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe执行失败: %v", err)
+	}
+	return parsePacketTimestamps(output)
+}
+
+// parsePacketTimestamps 解析ffprobe输出的每行一个pts_time（秒），忽略N/A
+func parsePacketTimestamps(output []byte) ([]time.Duration, error) {
+	var seconds []float64
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSuffix(strings.TrimSpace(scanner.Text()), ",")
+		if line == "" || line == "N/A" {
+			continue
+		}
+		v, err := strconv.ParseFloat(line, 64)
+		if err != nil {
+			continue
+		}
+		seconds = append(seconds, v)
+	}
+	if len(seconds) == 0 {
+		return nil, fmt.Errorf("未读取到任何帧时间戳")
+	}
+
+	// 数据包按解码顺序输出（B帧时与显示顺序不同），排序后即为显示顺序
+	sort.Float64s(seconds)
+	timestamps := make([]time.Duration, len(seconds))
+	for i, s := range seconds {
+		timestamps[i] = time.Duration((s - seconds[0]) * float64(time.Second))
+	}
+	return timestamps, nil
+}
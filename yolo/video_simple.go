@@ -54,12 +54,7 @@ func (svp *SimpleVideoProcessor) ProcessImageSequence(inputDir, outputDir string
 	}
 
 	// 支持的图像格式
-	supportedFormats := map[string]bool{
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".bmp":  true,
-	}
+	supportedFormats := imageExts
 
 	for i, file := range files {
 		if file.IsDir() {
@@ -110,12 +105,7 @@ func (svp *SimpleVideoProcessor) ProcessImageWithCallback(inputDir string, callb
 		return fmt.Errorf("读取输入目录失败: %v", err)
 	}
 
-	supportedFormats := map[string]bool{
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".bmp":  true,
-	}
+	supportedFormats := imageExts
 
 	for i, file := range files {
 		if file.IsDir() {
@@ -179,8 +169,7 @@ func (svp *SimpleVideoProcessor) drawDetectionsOnImage(img image.Image, detectio
 
 		if drawLabels {
 			// 绘制标签文本
-			label := fmt.Sprintf("%s %.2f", detection.Class, detection.Score)
-			svp.drawLabelOnImage(result, label, detection.Box)
+			svp.drawLabelOnImage(result, svp.detector.formatLabel(detection), detection.Box)
 		}
 	}
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image"
 	"image/draw"
+	"sync/atomic"
 	"time"
 
 	vidio "github.com/AlexEidt/Vidio"
@@ -13,13 +14,14 @@ import (
 type VidioVideoProcessor struct {
 	detector     *YOLO
 	optimization *VideoOptimization
+	options      *DetectionOptions // 处理时应用到每一帧的检测选项（nil时沿用检测器当前配置）
 }
 
-// NewVidioVideoProcessor 创建Vidio视频处理器
+// NewVidioVideoProcessor 创建Vidio视频处理器（复用检测器共享的优化实例）
 func NewVidioVideoProcessor(detector *YOLO) *VidioVideoProcessor {
 	return &VidioVideoProcessor{
 		detector:     detector,
-		optimization: NewVideoOptimizationWithCUDA(detector.config.UseGPU, detector.config.UseCUDA, detector.config.CUDADeviceID),
+		optimization: detector.GetVideoOptimization(),
 	}
 }
 
@@ -27,14 +29,26 @@ func NewVidioVideoProcessor(detector *YOLO) *VidioVideoProcessor {
 func NewVidioVideoProcessorWithOptions(detector *YOLO, options *DetectionOptions) *VidioVideoProcessor {
 	return &VidioVideoProcessor{
 		detector:     detector,
-		optimization: NewVideoOptimizationWithCUDA(detector.config.UseGPU, detector.config.UseCUDA, detector.config.CUDADeviceID),
+		optimization: detector.GetVideoOptimization(),
+		options:      options,
+	}
+}
+
+// applyOptions 处理开始前将检测选项应用到检测器，确保每帧的置信度过滤和NMS使用同一配置
+func (vp *VidioVideoProcessor) applyOptions() {
+	if vp.options != nil {
+		vp.detector.SetRuntimeConfig(vp.options)
+	} else if vp.detector.runtimeConfig == nil {
+		vp.detector.SetRuntimeConfig(vp.detector.resolveOptions(nil))
 	}
 }
 
 // ProcessVideo 处理视频文件并返回所有检测结果
 func (vp *VidioVideoProcessor) ProcessVideo(inputPath string) ([]VideoDetectionResult, error) {
+	vp.applyOptions()
+
 	// 打开视频文件
-	video, err := vidio.NewVideo(inputPath)
+	video, err := vp.detector.openVideo(inputPath)
 	if err != nil {
 		return nil, fmt.Errorf("无法打开视频文件: %v", err)
 	}
@@ -42,6 +56,7 @@ func (vp *VidioVideoProcessor) ProcessVideo(inputPath string) ([]VideoDetectionR
 
 	fmt.Printf("📹 视频信息: %dx%d, %.2f FPS, %d 帧, %.2f 秒\n",
 		video.Width(), video.Height(), video.FPS(), video.Frames(), video.Duration())
+	clock := vp.detector.newFrameClock(inputPath, video.FPS())
 
 	var results []VideoDetectionResult
 	frameCount := 0
@@ -59,25 +74,28 @@ func (vp *VidioVideoProcessor) ProcessVideo(inputPath string) ([]VideoDetectionR
 		
 
 		
+		detectStart := time.Now()
 		detections, err = vp.detector.detectImage(frameImg)
+		processingTime := time.Since(detectStart)
 		if err != nil {
 			fmt.Printf("⚠️  帧 %d 检测失败: %v\n", frameCount, err)
 			detections = []Detection{}
 		}
 
 		// 创建检测结果
-		timestamp := time.Duration(float64(frameCount)/video.FPS()*1000) * time.Millisecond
+		timestamp := clock.At(frameCount)
 		result := VideoDetectionResult{
-			FrameNumber: frameCount,
-			Timestamp:   timestamp,
-			Detections:  detections,
-			Image:       frameImg,
+			FrameNumber:    frameCount,
+			Timestamp:      timestamp,
+			Detections:     detections,
+			Image:          frameImg,
+			ProcessingTime: processingTime,
 		}
 		results = append(results, result)
 
 		// 进度提示
 		if frameCount%30 == 0 || frameCount == video.Frames() {
-			fmt.Printf("📊 已处理 %d/%d 帧...\n", frameCount, video.Frames())
+			vp.detector.verbosef("📊 已处理 %d/%d 帧...\n", frameCount, video.Frames())
 		}
 	}
 
@@ -90,10 +108,20 @@ func (vp *VidioVideoProcessor) GetOptimization() *VideoOptimization {
 	return vp.optimization
 }
 
+// SetOptimization 设置视频优化实例（用于复用已配置好的GC间隔、批处理大小和限流设置）
+func (vp *VidioVideoProcessor) SetOptimization(optimization *VideoOptimization) {
+	if optimization == nil {
+		return
+	}
+	vp.optimization = optimization
+}
+
 // ProcessVideoWithCallback 处理视频并对每帧调用回调函数（优化版本）
 func (vp *VidioVideoProcessor) ProcessVideoWithCallback(inputPath string, callback func(VideoDetectionResult)) error {
+	vp.applyOptions()
+
 	// 打开视频文件
-	video, err := vidio.NewVideo(inputPath)
+	video, err := vp.detector.openVideo(inputPath)
 	if err != nil {
 		return fmt.Errorf("无法打开视频文件: %v", err)
 	}
@@ -102,6 +130,7 @@ func (vp *VidioVideoProcessor) ProcessVideoWithCallback(inputPath string, callba
 	fmt.Printf("📹 视频信息: %dx%d, %.2f FPS, %d 帧\n",
 		video.Width(), video.Height(), video.FPS(), video.Frames())
 	fmt.Printf("🚀 性能优化: 批处理大小=%d, GPU加速=%v\n", vp.optimization.GetBatchSize(), vp.optimization.IsGPUEnabled())
+	clock := vp.detector.newFrameClock(inputPath, video.FPS())
 
 	frameCount := 0
 	startTime := time.Now()
@@ -115,8 +144,10 @@ func (vp *VidioVideoProcessor) ProcessVideoWithCallback(inputPath string, callba
 		// 将帧缓冲区转换为Go图像
 		frameImg := convertFrameBufferToImage(video.FrameBuffer(), video.Width(), video.Height())
 
-		// 使用优化的检测方法
+		// 使用优化的检测方法（记录预处理+推理耗时）
+		detectStart := time.Now()
 		detections, err := vp.optimizedDetectImage(frameImg)
+		processingTime := time.Since(detectStart)
 		if err != nil {
 			// 减少错误输出频率
 			if frameCount%100 == 0 {
@@ -126,12 +157,13 @@ func (vp *VidioVideoProcessor) ProcessVideoWithCallback(inputPath string, callba
 		}
 
 		// 创建检测结果并调用回调
-		timestamp := time.Duration(float64(frameCount)/video.FPS()*1000) * time.Millisecond
+		timestamp := clock.At(frameCount)
 		result := VideoDetectionResult{
-			FrameNumber: frameCount,
-			Timestamp:   timestamp,
-			Detections:  detections,
-			Image:       frameImg,
+			FrameNumber:    frameCount,
+			Timestamp:      timestamp,
+			Detections:     detections,
+			Image:          frameImg,
+			ProcessingTime: processingTime,
 		}
 		callback(result)
 
@@ -139,26 +171,34 @@ func (vp *VidioVideoProcessor) ProcessVideoWithCallback(inputPath string, callba
 		if frameCount%100 == 0 {
 			elapsed := time.Since(startTime)
 			fps := float64(frameCount) / elapsed.Seconds()
-			fmt.Printf("📊 已处理 %d/%d 帧, 当前FPS: %.1f\n", frameCount, video.Frames(), fps)
+			vp.detector.verbosef("📊 已处理 %d/%d 帧, 当前FPS: %.1f\n", frameCount, video.Frames(), fps)
 		}
 	}
 
+	// 启用部分结果时，读取在视频结束前中断视为解码失败，由调用方保留已完成的帧
+	if vp.detector.runtimeConfig != nil && vp.detector.runtimeConfig.PartialResults &&
+		video.Frames() > 0 && frameCount < video.Frames() {
+		return fmt.Errorf("视频读取在第 %d 帧后中断（共 %d 帧）", frameCount, video.Frames())
+	}
+
 	elapsed := time.Since(startTime)
 	avgFPS := float64(frameCount) / elapsed.Seconds()
 	fmt.Printf("✅ 视频处理完成！共处理 %d 帧, 平均FPS: %.1f, 总耗时: %v\n", frameCount, avgFPS, elapsed)
 	return nil
 }
 
-// optimizedDetectImage 优化的图像检测方法
+// optimizedDetectImage 检测单帧，与图片检测走同一流水线
+// （运动门控、裁剪区域、后处理器、框裁剪；启用GPU时由runDetection使用优化模块推理）
 func (vp *VidioVideoProcessor) optimizedDetectImage(img image.Image) ([]Detection, error) {
-	// 使用优化模块进行检测
-	return vp.optimization.OptimizedDetectImage(vp.detector, img)
+	return vp.detector.detectImage(img)
 }
 
 // SaveVideoWithDetections 保存带检测框的视频
 func (vp *VidioVideoProcessor) SaveVideoWithDetections(inputPath, outputPath string) error {
+	vp.applyOptions()
+
 	// 打开输入视频
-	video, err := vidio.NewVideo(inputPath)
+	video, err := vp.detector.openVideo(inputPath)
 	if err != nil {
 		return fmt.Errorf("无法打开视频文件: %v", err)
 	}
@@ -178,12 +218,45 @@ func (vp *VidioVideoProcessor) SaveVideoWithDetections(inputPath, outputPath str
 
 	fmt.Printf("📹 开始处理视频: %s -> %s\n", inputPath, outputPath)
 	frameCount := 0
+	clock := vp.detector.newFrameClock(inputPath, video.FPS())
+	totalFrames := video.Frames()
+
+	// 绘制和编码在独立协程中进行，通过有界通道与读取+检测流水线并行
+	jobs := make(chan annotatedFrameJob, saveVideoPipelineDepth)
+	writeDone := make(chan error, 1)
+	var writeFailed atomic.Bool
+
+	go func() {
+		var writeErr error
+		for job := range jobs {
+			if writeErr != nil {
+				continue // 出错后丢弃剩余帧，等待读取端退出
+			}
 
-	// 逐帧处理
-	for video.Read() {
+			// 绘制检测结果和帧叠加信息
+			timestamp := clock.At(job.frameNumber)
+			resultImg := vp.detector.annotateFrame(job.frame, job.detections, timestamp)
+
+			// 将图像转换回帧缓冲区并写入
+			if err := writer.Write(convertImageToFrameBuffer(resultImg)); err != nil {
+				writeErr = fmt.Errorf("写入帧失败: %v", err)
+				writeFailed.Store(true)
+				continue
+			}
+
+			// 进度提示
+			if job.frameNumber%30 == 0 {
+				vp.detector.verbosef("📊 已处理 %d/%d 帧...\n", job.frameNumber, totalFrames)
+			}
+		}
+		writeDone <- writeErr
+	}()
+
+	// 逐帧读取并检测
+	for video.Read() && !writeFailed.Load() {
 		frameCount++
 
-		// 将帧缓冲区转换为Go图像
+		// 将帧缓冲区转换为Go图像（每帧独立的图像，可安全交给写入协程）
 		frameImg := convertFrameBufferToImage(video.FrameBuffer(), video.Width(), video.Height())
 
 		// YOLO检测
@@ -192,29 +265,28 @@ func (vp *VidioVideoProcessor) SaveVideoWithDetections(inputPath, outputPath str
 			detections = []Detection{}
 		}
 
-		// 绘制检测结果
-		var resultImg image.Image = frameImg
-		if len(detections) > 0 {
-			resultImg = vp.detector.drawDetectionsOnImage(frameImg, detections)
-		}
-
-		// 将图像转换回帧缓冲区并写入
-		frameBuffer := convertImageToFrameBuffer(resultImg)
-		err = writer.Write(frameBuffer)
-		if err != nil {
-			return fmt.Errorf("写入帧失败: %v", err)
-		}
+		jobs <- annotatedFrameJob{frame: frameImg, detections: detections, frameNumber: frameCount}
+	}
 
-		// 进度提示
-		if frameCount%30 == 0 {
-			fmt.Printf("📊 已处理 %d/%d 帧...\n", frameCount, video.Frames())
-		}
+	close(jobs)
+	if err := <-writeDone; err != nil {
+		return err
 	}
 
 	fmt.Printf("✅ 视频保存完成！共处理 %d 帧，保存为 %s\n", frameCount, outputPath)
 	return nil
 }
 
+// saveVideoPipelineDepth 保存视频时读取/检测与绘制/编码之间缓冲的最大帧数
+const saveVideoPipelineDepth = 8
+
+// annotatedFrameJob 待绘制并写入的帧
+type annotatedFrameJob struct {
+	frame       image.Image
+	detections  []Detection
+	frameNumber int
+}
+
 // convertFrameBufferToImage 将Vidio的帧缓冲区转换为Go图像
 func convertFrameBufferToImage(frameBuffer []byte, width, height int) image.Image {
 	// Vidio返回RGBA格式的字节数组
@@ -1,15 +1,18 @@
 package yolo
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
 	"image/jpeg"
 	"image/png"
+	"io"
+	"math"
 	"os"
 	"path/filepath"
-	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -17,7 +20,6 @@ import (
 
 	"os/exec"
 
-	vidio "github.com/AlexEidt/Vidio"
 	"github.com/disintegration/imaging"
 	ort "github.com/yalue/onnxruntime_go"
 	"golang.org/x/image/font"
@@ -65,10 +67,11 @@ var globalClasses []string
 
 // VideoDetectionResult 视频检测结果
 type VideoDetectionResult struct {
-	FrameNumber int
-	Timestamp   time.Duration
-	Detections  []Detection
-	Image       image.Image
+	FrameNumber    int
+	Timestamp      time.Duration
+	Detections     []Detection
+	Image          image.Image
+	ProcessingTime time.Duration // 本帧预处理+推理耗时
 }
 
 // SetClasses 设置全局类别列表
@@ -87,6 +90,17 @@ type Detection struct {
 	Score   float32
 	ClassID int
 	Class   string
+	TrackID int // 跟踪ID（0表示未跟踪）
+
+	Attributes map[string]string // 附加属性（如二级分类器识别出的车牌号、物种）
+	TopClasses []ClassScore      // 得分最高的K个类别（按分数降序，需启用WithTopClasses）
+}
+
+// ClassScore 类别及其分数
+type ClassScore struct {
+	ClassID int
+	Class   string
+	Score   float32
 }
 
 // DetectionResults 检测结果集合
@@ -96,14 +110,13 @@ type DetectionResults struct {
 	detector   *YOLO
 	// 新增：存储视频的逐帧检测结果
 	VideoResults []VideoDetectionResult
+	// 图片检测的各阶段耗时（视频结果的逐帧耗时见VideoDetectionResult.ProcessingTime）
+	Timings Timings
 }
 
 // Save 保存检测结果到指定路径
+// 视频即使没有任何检测结果也会保存（未标注的视频），仅图片无检测结果时返回错误
 func (dr *DetectionResults) Save(outputPath string) error {
-	if len(dr.Detections) == 0 {
-		return fmt.Errorf("没有检测结果可保存")
-	}
-
 	if dr.InputPath == "" {
 		return fmt.Errorf("没有输入文件路径信息")
 	}
@@ -120,14 +133,28 @@ func (dr *DetectionResults) Save(outputPath string) error {
 		}
 	} else {
 		// 图片：保存带检测框的图片
+		if len(dr.Detections) == 0 {
+			return fmt.Errorf("没有检测结果可保存")
+		}
 		_, err := dr.detector.DetectAndSave(dr.InputPath, outputPath)
 		return err
 	}
 }
 
+// detectionsByFrame 按帧号索引视频逐帧检测结果，对跳帧、缺帧和乱序结果都能正确匹配
+func (dr *DetectionResults) detectionsByFrame() map[int][]Detection {
+	byFrame := make(map[int][]Detection, len(dr.VideoResults))
+	for _, result := range dr.VideoResults {
+		byFrame[result.FrameNumber] = append(byFrame[result.FrameNumber], result.Detections...)
+	}
+	return byFrame
+}
+
 // 全局变量用于管理ONNX Runtime环境
+// ortRefCount 为存活的检测器数量，最后一个检测器Close时自动销毁环境
 var (
 	ortInitialized bool
+	ortRefCount    int
 	ortMutex       sync.Mutex
 )
 
@@ -135,6 +162,11 @@ var (
 type YOLO struct {
 	config  *YOLOConfig
 	session *ort.DynamicAdvancedSession
+	// 模型和类别文件路径（创建同配置的工作检测器时使用）
+	modelPath string
+	classPath string
+	// 实际启用的执行提供者（ActiveProvider）
+	provider string
 	// 运行时配置
 	runtimeConfig *DetectionOptions
 	// 添加状态跟踪
@@ -144,11 +176,44 @@ type YOLO struct {
 	// 模型信息
 	modelInputShape  []int64 // 模型实际输入形状
 	modelOutputShape []int64 // 模型实际输出形状
+	outputShapeMu    sync.Mutex // 保护modelOutputShape（批量检测的并发推理会更新它）
+	modelInputDims   []int64 // 模型声明的输入维度（<=0 表示动态维度）
 	// GPU极致优化模块
-	optimization *VideoOptimization
+	optimization   *VideoOptimization
+	optimizationMu sync.Mutex // 保护GetVideoOptimization的延迟创建，避免并发首次调用各自创建实例
+	// 默认检测选项（传入nil选项时使用，未设置时使用包级默认值）
+	defaultOptions *DetectionOptions
+	// 帧间IOU跟踪（为检测结果分配TrackID）
+	tracker iouTracker
+	// 跟踪轨迹（按TrackID记录最近的中心点，用于绘制运动轨迹）
+	trackTrails map[int]*trackTrail
+	trailsMu    sync.Mutex // 检测流水线更新轨迹时，保存视频的写入协程可能同时绘制
+	// 保存视频时叠加的处理帧率
+	fps FPSMeter
+	// 运动门控状态（静止画面复用上一次检测结果）
+	motion motionGate
+	// 串行执行DetectAsync提交的检测（检测器非并发安全）
+	asyncMu sync.Mutex
+	// 类别数检查（首次推理时比较模型类别数与类别列表，仅检查一次）
+	classCountOnce sync.Once // 类别数只在首次推理（或加载时）检查一次
+	classCountErr  error
+	// 最近一次单帧检测的各阶段耗时
+	timings   Timings
+	timingsMu sync.Mutex
+
+	largeImageWarnOnce sync.Once // 输入图像超过预处理尺寸上限的警告只输出一次
+
+	// 空闲保温（最近一次推理的UnixNano时间，原子访问）
+	lastInference int64
+	keepWarm      *keepWarmLoop
+
+	// 超时后仍在后台运行的推理（0或1，原子访问），结束前会话不再接受新的推理
+	abandonedRun int32
+	abandonedWG  sync.WaitGroup // Close等待超时的推理结束后才销毁会话
 }
 
 // NewYOLO 创建新的YOLO检测器（配置文件必须，YOLOConfig可选）
+// 仅支持ONNX模型，其他格式（.pt、.engine等）返回ErrUnsupportedModelFormat
 func NewYOLO(modelPath, configPath string, config ...*YOLOConfig) (*YOLO, error) {
 	// 使用传入的配置，如果没有则使用默认配置
 	var yoloConfig *YOLOConfig
@@ -158,6 +223,24 @@ func NewYOLO(modelPath, configPath string, config ...*YOLOConfig) (*YOLO, error)
 		yoloConfig = DefaultConfig()
 	}
 
+	if err := yoloConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	// 未显式传入路径时，使用配置中的ModelPath/ClassPath
+	if modelPath == "" {
+		modelPath = yoloConfig.ModelPath
+	}
+	if configPath == "" {
+		configPath = yoloConfig.ClassPath
+	}
+	if modelPath == "" {
+		return nil, fmt.Errorf("未指定模型文件路径")
+	}
+	if err := checkModelFormat(modelPath); err != nil {
+		return nil, err
+	}
+
 	// 加载配置文件（必须）
 	configManager := NewConfigManager(configPath)
 	err := configManager.LoadConfig()
@@ -174,26 +257,8 @@ func NewYOLO(modelPath, configPath string, config ...*YOLOConfig) (*YOLO, error)
 		}
 	}
 
-	// 加载类别信息
-	err = loadClassesFromYAML(configPath)
-	if err != nil {
-		fmt.Printf("⚠️  加载类别信息失败: %v\n", err)
-		fmt.Println("💡 将使用默认类别列表")
-		// 设置默认类别
-		defaultClasses := []string{
-			"person", "bicycle", "car", "motorcycle", "airplane", "bus", "train", "truck", "boat",
-			"traffic light", "fire hydrant", "stop sign", "parking meter", "bench", "bird", "cat", "dog",
-			"horse", "sheep", "cow", "elephant", "bear", "zebra", "giraffe", "backpack", "umbrella",
-			"handbag", "tie", "suitcase", "frisbee", "skis", "snowboard", "sports ball", "kite",
-			"baseball bat", "baseball glove", "skateboard", "surfboard", "tennis racket", "bottle",
-			"wine glass", "cup", "fork", "knife", "spoon", "bowl", "banana", "apple", "sandwich",
-			"orange", "broccoli", "carrot", "hot dog", "pizza", "donut", "cake", "chair", "couch",
-			"potted plant", "bed", "dining table", "toilet", "tv", "laptop", "mouse", "remote",
-			"keyboard", "cell phone", "microwave", "oven", "toaster", "sink", "refrigerator",
-			"book", "clock", "vase", "scissors", "teddy bear", "hair drier", "toothbrush",
-		}
-		SetClasses(defaultClasses)
-	}
+	// 加载类别信息（类别文件优先，失败时在ONNX Runtime初始化后尝试读取模型元数据）
+	classErr := loadClassesFromYAML(configPath)
 
 	// 设置ONNX Runtime库路径
 	if yoloConfig.LibraryPath != "" {
@@ -207,20 +272,59 @@ func NewYOLO(modelPath, configPath string, config ...*YOLOConfig) (*YOLO, error)
 	if !ortInitialized {
 		err := ort.InitializeEnvironment()
 		if err != nil {
-			return nil, fmt.Errorf("无法初始化ONNX Runtime: %v", err)
+			return nil, fmt.Errorf("%w: 无法初始化ONNX Runtime: %w", ErrModelLoad, err)
 		}
 		ortInitialized = true
 	}
 
+	// 占用环境引用，创建失败时释放（defer在ortMutex解锁前执行）
+	ortRefCount++
+	created := false
+	defer func() {
+		if !created {
+			releaseEnvironmentLocked()
+		}
+	}()
+
+	// 类别文件不可用时使用模型元数据中的类别（Ultralytics导出的names）
+	if classErr != nil {
+		if metaErr := loadClassesFromModelMetadata(modelPath); metaErr == nil {
+			classErr = nil
+		} else {
+			fmt.Printf("⚠️  模型元数据中没有类别信息: %v\n", metaErr)
+		}
+	}
+	if classErr != nil && yoloConfig.StrictClasses {
+		return nil, fmt.Errorf("加载类别信息失败: %v", classErr)
+	}
+	if classErr != nil {
+		fmt.Printf("⚠️  加载类别信息失败: %v\n", classErr)
+		fmt.Println("💡 将使用默认类别列表")
+		// 设置默认类别
+		defaultClasses := []string{
+			"person", "bicycle", "car", "motorcycle", "airplane", "bus", "train", "truck", "boat",
+			"traffic light", "fire hydrant", "stop sign", "parking meter", "bench", "bird", "cat", "dog",
+			"horse", "sheep", "cow", "elephant", "bear", "zebra", "giraffe", "backpack", "umbrella",
+			"handbag", "tie", "suitcase", "frisbee", "skis", "snowboard", "sports ball", "kite",
+			"baseball bat", "baseball glove", "skateboard", "surfboard", "tennis racket", "bottle",
+			"wine glass", "cup", "fork", "knife", "spoon", "bowl", "banana", "apple", "sandwich",
+			"orange", "broccoli", "carrot", "hot dog", "pizza", "donut", "cake", "chair", "couch",
+			"potted plant", "bed", "dining table", "toilet", "tv", "laptop", "mouse", "remote",
+			"keyboard", "cell phone", "microwave", "oven", "toaster", "sink", "refrigerator",
+			"book", "clock", "vase", "scissors", "teddy bear", "hair drier", "toothbrush",
+		}
+		SetClasses(defaultClasses)
+	}
+
 	// 创建会话选项
 	sessionOptions, err := ort.NewSessionOptions()
 	if err != nil {
-		return nil, fmt.Errorf("无法创建会话选项: %v", err)
+		return nil, fmt.Errorf("%w: 无法创建会话选项: %w", ErrModelLoad, err)
 	}
 
 	// 设置会话选项以提升性能
-	// 根据CPU核心数动态调整线程数
-	numCPU := runtime.NumCPU()
+	// 根据可用CPU数（考虑容器CPU配额）动态调整线程数
+	numCPU := EffectiveCPUs()
 	optimalThreads := numCPU
 	if numCPU > 8 {
 		// 对于高核心数CPU，使用75%的核心以避免过度竞争
@@ -259,6 +363,7 @@ func NewYOLO(modelPath, configPath string, config ...*YOLOConfig) (*YOLO, error)
 	}
 
 	// 如果启用GPU，使用用户成功案例的CUDA初始化方法
+	provider := ProviderCPU
 	if yoloConfig.UseGPU {
 		fmt.Println("🚀 启用GPU加速 - 使用优化的CUDA初始化方法")
 
@@ -266,7 +371,7 @@ func NewYOLO(modelPath, configPath string, config ...*YOLOConfig) (*YOLO, error)
 		cudaOptions, err := ort.NewCUDAProviderOptions()
 		if err != nil {
 			sessionOptions.Destroy()
-			return nil, fmt.Errorf("CUDA Provider 创建失败: %v", err)
+			return nil, fmt.Errorf("%w: CUDA Provider 创建失败: %w", ErrGPUUnavailable, err)
 		}
 		defer cudaOptions.Destroy()
 
@@ -276,37 +381,47 @@ func NewYOLO(modelPath, configPath string, config ...*YOLOConfig) (*YOLO, error)
 		})
 		if err != nil {
 			sessionOptions.Destroy()
-			return nil, fmt.Errorf("CUDA 配置失败: %v", err)
+			return nil, fmt.Errorf("%w: CUDA 配置失败: %w", ErrGPUUnavailable, err)
 		}
 
 		// 步骤3: 添加CUDA执行提供者
 		err = sessionOptions.AppendExecutionProviderCUDA(cudaOptions)
 		if err != nil {
 			sessionOptions.Destroy()
-			return nil, fmt.Errorf("CUDA EP 初始化失败: %v", err)
+			return nil, fmt.Errorf("%w: CUDA EP 初始化失败: %w", ErrGPUUnavailable, err)
 		}
 
+		provider = ProviderCUDA
 		fmt.Println("✅ CUDA 初始化成功，已启用 GPU 推理")
 	} else {
 		fmt.Println("💻 使用CPU模式")
 	}
 
-	// 加载模型
-	session, err := ort.NewDynamicAdvancedSession(modelPath,
-		[]string{"images"}, []string{"output0"}, sessionOptions)
+	// 加载模型（锚框模型使用模型声明的全部输出头）
+	inputNames, outputNames := []string{"images"}, []string{"output0"}
+	if yoloConfig.Anchors != nil {
+		inputNames, outputNames, err = modelIONames(modelPath)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 无法获取模型输入输出名称: %w", ErrModelLoad, err)
+		}
+		if len(outputNames) != len(yoloConfig.Anchors.Strides) {
+			return nil, fmt.Errorf("%w: 模型有 %d 个输出，但锚框配置了 %d 个输出头", ErrModelLoad, len(outputNames), len(yoloConfig.Anchors.Strides))
+		}
+	}
+	session, err := ort.NewDynamicAdvancedSession(modelPath, inputNames, outputNames, sessionOptions)
 	if err != nil {
-		return nil, fmt.Errorf("无法加载模型文件 '%s': %v", modelPath, err)
+		return nil, fmt.Errorf("%w: 无法加载模型文件 '%s': %w", ErrModelLoad, modelPath, err)
 	}
 
 	// 获取模型输入输出信息
 	inputInfos, outputInfos, err := ort.GetInputOutputInfo(modelPath)
 	if err != nil {
 		session.Destroy()
-		return nil, fmt.Errorf("无法获取模型输入输出信息: %v", err)
+		return nil, fmt.Errorf("%w: 无法获取模型输入输出信息: %w", ErrModelLoad, err)
 	}
 	if len(inputInfos) == 0 || len(outputInfos) == 0 {
 		session.Destroy()
-		return nil, fmt.Errorf("模型输入或输出信息为空")
+		return nil, fmt.Errorf("%w: 模型输入或输出信息为空", ErrModelLoad)
 	}
 
 	// 注意：InputOutputInfo结构体在onnxruntime_go v1.21.0中没有GetShape()方法
@@ -324,9 +439,9 @@ func NewYOLO(modelPath, configPath string, config ...*YOLOConfig) (*YOLO, error)
 		fmt.Printf("📊 使用正方形输入形状: %dx%d -> %v\n", yoloConfig.InputSize, yoloConfig.InputSize, modelInputShape)
 	}
 
-	// 输出形状设置为标准YOLO格式，避免动态维度导致的张量创建错误
-	modelOutputShape = []int64{1, 84, 8400} // 标准YOLO输出格式
-	fmt.Printf("📊 输出形状: %v (标准YOLO格式)\n", modelOutputShape)
+	// 输出形状取模型声明的维度（动态维度为-1），推理时由ONNX Runtime按实际形状分配输出
+	modelOutputShape = append([]int64(nil), outputInfos[0].Dimensions...)
+	fmt.Printf("📊 模型声明的输出形状: %v\n", modelOutputShape)
 
 	// 创建YOLO实例
 	yolo := &YOLO{
@@ -334,10 +449,24 @@ func NewYOLO(modelPath, configPath string, config ...*YOLOConfig) (*YOLO, error)
 		session:          session,
 		modelInputShape:  modelInputShape,
 		modelOutputShape: modelOutputShape,
+		modelInputDims:   inputInfos[0].Dimensions,
+		modelPath:        modelPath,
+		classPath:        configPath,
+		provider:         provider,
+	}
+
+	// 模型声明了固定输出形状时在加载阶段检查类别数，不必等到首次推理
+	if numClasses, ok := yolo.declaredClassCount(); ok {
+		yolo.checkClassCount(numClasses)
+		if err := yolo.classCountError(); err != nil {
+			session.Destroy()
+			return nil, err
+		}
 	}
 
 	// 初始化GPU极致优化模块，支持CUDA加速
-	yolo.optimization = NewVideoOptimizationWithCUDA(yoloConfig.UseGPU, yoloConfig.UseCUDA, yoloConfig.CUDADeviceID)
+	yolo.optimization = NewVideoOptimizationWithOptions(yoloConfig.UseGPU, yoloConfig.UseCUDA, yoloConfig.CUDADeviceID, yoloConfig.Parallelism)
+	yolo.applyOptimizationConfig()
 	if yolo.optimization.IsGPUEnabled() || yolo.optimization.IsCUDAEnabled() {
 		fmt.Printf("🚀 GPU极致优化模块已初始化 (GPU: %v, CUDA: %v, 批处理大小: %d, 并行工作线程: %d)\n",
 			yolo.optimization.IsGPUEnabled(),
@@ -346,6 +475,9 @@ func NewYOLO(modelPath, configPath string, config ...*YOLOConfig) (*YOLO, error)
 			yolo.optimization.GetParallelWorkers())
 	}
 
+	yolo.startKeepWarm(yoloConfig.KeepWarmInterval)
+
+	created = true
 	return yolo, nil
 }
 
@@ -354,13 +486,101 @@ func NewYOLOWithConfig(modelPath, configPath string, config *YOLOConfig) (*YOLO,
 	return NewYOLO(modelPath, configPath, config)
 }
 
-// Close 关闭YOLO检测器
+// NewYOLOFromModelConfig 仅通过YOLOConfig创建检测器（模型和类别路径取自ModelPath/ClassPath）
+func NewYOLOFromModelConfig(config *YOLOConfig) (*YOLO, error) {
+	if config == nil {
+		return nil, fmt.Errorf("配置不能为空")
+	}
+	return NewYOLO(config.ModelPath, config.ClassPath, config)
+}
+
+// SetInputSize 运行时切换模型输入分辨率（仅支持动态输入尺寸的模型，无需重建会话）
+// 例如实时预览使用较低分辨率，最终处理使用较高分辨率；宽高必须是32的倍数
+func (y *YOLO) SetInputSize(width, height int) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("无效的输入尺寸: %dx%d", width, height)
+	}
+	if width%32 != 0 || height%32 != 0 {
+		return fmt.Errorf("输入尺寸必须是32的倍数: %dx%d", width, height)
+	}
+
+	// 校验模型声明的输入维度 [N, C, H, W]，固定维度必须与请求一致
+	if len(y.modelInputDims) == 4 {
+		fixedHeight, fixedWidth := y.modelInputDims[2], y.modelInputDims[3]
+		if (fixedHeight > 0 && fixedHeight != int64(height)) || (fixedWidth > 0 && fixedWidth != int64(width)) {
+			return fmt.Errorf("模型输入尺寸固定为 %dx%d，无法切换为 %dx%d", fixedWidth, fixedHeight, width, height)
+		}
+	}
+
+	y.config.InputWidth = width
+	y.config.InputHeight = height
+	if width == height {
+		y.config.InputSize = width
+	}
+	y.modelInputShape = []int64{1, 3, int64(height), int64(width)}
+
+	// 按YOLO的8/16/32三个步长重新计算输出检测框数量，特征数和输出布局保持不变
+	y.outputShapeMu.Lock()
+	defer y.outputShapeMu.Unlock()
+	features := int64(84)
+	featuresLast := false
+	if len(y.modelOutputShape) == 3 {
+		featuresLast = y.outputFeaturesLast(y.modelOutputShape)
+		if featuresLast && y.modelOutputShape[2] > 0 {
+			features = y.modelOutputShape[2]
+		} else if !featuresLast && y.modelOutputShape[1] > 0 {
+			features = y.modelOutputShape[1]
+		}
+	}
+	numBoxes := int64(0)
+	for _, stride := range []int{8, 16, 32} {
+		numBoxes += int64((width / stride) * (height / stride))
+	}
+	y.modelOutputShape = []int64{1, features, numBoxes}
+	if featuresLast {
+		y.modelOutputShape = []int64{1, numBoxes, features}
+	}
+
+	fmt.Printf("📐 输入尺寸已切换为 %dx%d，输出形状: %v\n", width, height, y.modelOutputShape)
+	return nil
+}
+
+// Close 关闭YOLO检测器，最后一个检测器关闭时同时销毁ONNX Runtime环境（重复调用无副作用）
+// 有超时后仍未结束的推理时，会等待它结束再销毁会话
 func (y *YOLO) Close() {
-	if y.session != nil {
-		y.session.Destroy()
+	if y.session == nil {
+		return
+	}
+	y.stopKeepWarm()
+	if y.inferenceHung() {
+		fmt.Println("⏳ 等待超时的推理结束后再关闭会话...")
+	}
+	y.abandonedWG.Wait() // 超时的推理仍在使用会话，提前销毁会导致释放后使用
+	y.session.Destroy()
+	y.session = nil
+
+	ortMutex.Lock()
+	defer ortMutex.Unlock()
+	releaseEnvironmentLocked()
+}
+
+// releaseEnvironmentLocked 释放一个环境引用，引用归零时销毁环境（调用方须持有ortMutex）
+func releaseEnvironmentLocked() {
+	if ortRefCount > 0 {
+		ortRefCount--
+	}
+	if ortRefCount == 0 && ortInitialized {
+		ort.DestroyEnvironment()
+		ortInitialized = false
+	}
+}
+
+// verbosef 输出逐帧进度和调试信息，静默模式下不输出
+func (y *YOLO) verbosef(format string, args ...interface{}) {
+	if y != nil && y.config != nil && y.config.Quiet {
+		return
 	}
-	// 注意：不要在这里调用 ort.DestroyEnvironment()
-	// 因为可能有其他检测器还在使用
+	fmt.Printf(format, args...)
 }
 
 // SetRuntimeConfig 设置运行时检测配置
@@ -368,7 +588,25 @@ func (y *YOLO) SetRuntimeConfig(options *DetectionOptions) {
 	y.runtimeConfig = options
 }
 
-// DestroyEnvironment 销毁ONNX Runtime环境（在所有检测器都关闭后调用）
+// SetDefaultOptions 设置检测器的默认检测选项，之后传入nil选项时使用该配置代替DefaultDetectionOptions()
+// 传入nil恢复包级默认值
+func (y *YOLO) SetDefaultOptions(options *DetectionOptions) {
+	y.defaultOptions = options
+}
+
+// resolveOptions 返回实际使用的检测选项：传入的选项优先，其次检测器默认选项，最后包级默认值
+func (y *YOLO) resolveOptions(options *DetectionOptions) *DetectionOptions {
+	if options != nil {
+		return options
+	}
+	if y.defaultOptions != nil {
+		return y.defaultOptions
+	}
+	return DefaultDetectionOptions()
+}
+
+// DestroyEnvironment 强制销毁ONNX Runtime环境
+// 通常无需调用：最后一个检测器Close时会自动销毁；仍有检测器未关闭时调用会使其无法继续推理
 func DestroyEnvironment() {
 	ortMutex.Lock()
 	defer ortMutex.Unlock()
@@ -376,39 +614,57 @@ func DestroyEnvironment() {
 		ort.DestroyEnvironment()
 		ortInitialized = false
 	}
+	ortRefCount = 0
 }
 
 // DetectImage 检测单张图片
 func (y *YOLO) DetectImage(imagePath string) ([]Detection, error) {
 	// 如果没有设置运行时配置，使用默认配置
 	if y.runtimeConfig == nil {
-		y.runtimeConfig = DefaultDetectionOptions()
+		y.runtimeConfig = y.resolveOptions(nil)
+	}
+
+	var timings Timings
+	defer y.finishTimings(&timings, time.Now())
+
+	// 设置了裁剪区域时按内存图像检测该区域
+	if y.runtimeConfig.CropRegion != (image.Rectangle{}) {
+		preStart := time.Now()
+		img, err := y.openImage(imagePath)
+		if err != nil {
+			return nil, fmt.Errorf("无法打开图像: %w", err)
+		}
+		timings.addPreprocess(time.Since(preStart))
+		return y.runDetection(img, &timings)
 	}
 
 	// 如果启用了GPU且优化模块可用，使用极致优化检测
 	if y.config.UseGPU && y.optimization != nil {
 		// 加载图像
-		img, err := imaging.Open(imagePath)
+		preStart := time.Now()
+		img, err := y.openImage(imagePath)
 		if err != nil {
-			return nil, fmt.Errorf("无法打开图像: %v", err)
+			return nil, fmt.Errorf("无法打开图像: %w", err)
 		}
+		timings.addPreprocess(time.Since(preStart))
 
 		// 使用极致优化检测
-		detections, err := y.optimization.OptimizedDetectImage(y, img)
+		detections, err := y.optimization.optimizedDetectImage(y, img, &timings)
 		if err != nil {
-			return nil, fmt.Errorf("GPU极致优化检测失败: %v", err)
+			return nil, fmt.Errorf("GPU极致优化检测失败: %w", err)
 		}
 
 		fmt.Printf("🚀 使用GPU极致优化检测 (批处理大小: %d, 并行工作线程: %d)\n",
 			y.optimization.GetBatchSize(), y.optimization.GetParallelWorkers())
 
-		return detections, nil
+		return y.finalizeDetections(img, detections)
 	}
 
 	// 加载图像以获取原始尺寸
-	img, err := imaging.Open(imagePath)
+	preStart := time.Now()
+	img, err := y.openImage(imagePath)
 	if err != nil {
-		return nil, fmt.Errorf("无法打开图像: %v", err)
+		return nil, fmt.Errorf("无法打开图像: %w", err)
 	}
 
 	// 获取原始图像尺寸
@@ -419,68 +675,16 @@ func (y *YOLO) DetectImage(imagePath string) ([]Detection, error) {
 	// 预处理图像
 	inputData, err := y.preprocessImage(imagePath)
 	if err != nil {
-		return nil, fmt.Errorf("图像预处理失败: %v", err)
+		return nil, fmt.Errorf("图像预处理失败: %w", err)
 	}
+	timings.addPreprocess(time.Since(preStart))
 
-	// 创建输入张量
-	var inputShape ort.Shape
-	if y.config.InputWidth > 0 && y.config.InputHeight > 0 {
-		// 使用自定义的宽度和高度
-		inputShape = ort.NewShape(1, 3, int64(y.config.InputHeight), int64(y.config.InputWidth))
-	} else {
-		// 使用正方形输入尺寸
-		inputShape = ort.NewShape(1, 3, int64(y.config.InputSize), int64(y.config.InputSize))
-	}
-	inputTensor, err := ort.NewTensor(inputShape, inputData)
-	if err != nil {
-		return nil, fmt.Errorf("无法创建输入张量: %v", err)
-	}
-	defer inputTensor.Destroy()
-
-	// 创建输出张量（智能适配模型输出形状）
-	var outputShape ort.Shape
-	var outputDataSize int
-
-	// 如果是第一次推理或者modelOutputShape包含动态维度，使用标准形状进行探测
-	if len(y.modelOutputShape) == 0 || containsDynamicDimension(y.modelOutputShape) {
-		// 使用标准YOLO输出形状进行第一次推理
-		outputShape = ort.NewShape(1, 84, 8400)
-		outputDataSize = 1 * 84 * 8400
-		fmt.Println("🔍 使用标准YOLO输出形状进行模型探测: [1, 84, 8400]")
-	} else {
-		// 使用已知的模型输出形状
-		outputShape = ort.NewShape(y.modelOutputShape...)
-		outputDataSize = 1
-		for _, dim := range y.modelOutputShape {
-			outputDataSize *= int(dim)
-		}
-		fmt.Printf("📊 使用已知模型输出形状: %v\n", y.modelOutputShape)
-	}
-
-	outputData := make([]float32, outputDataSize)
-	outputTensor, err := ort.NewTensor(outputShape, outputData)
-
+	// 推理并解析检测结果（模型输入尺寸坐标）
+	detections, err := y.inferDetections(inputData, true, &timings)
 	if err != nil {
-		return nil, fmt.Errorf("无法创建输出张量: %v", err)
-	}
-	defer outputTensor.Destroy()
-
-	// 运行推理
-	err = y.session.Run([]ort.Value{inputTensor}, []ort.Value{outputTensor})
-	if err != nil {
-		return nil, fmt.Errorf("推理失败: %v", err)
-	}
-
-	// 获取实际的输出形状并更新模型信息
-	actualOutputShape := outputTensor.GetShape()
-	if len(y.modelOutputShape) == 0 || containsDynamicDimension(y.modelOutputShape) {
-		y.modelOutputShape = actualOutputShape
-		fmt.Printf("✅ 自动检测到模型实际输出形状: %v\n", actualOutputShape)
+		return nil, err
 	}
 
-	// 解析检测结果
-	detections := y.parseDetections(outputTensor.GetData(), actualOutputShape)
-
 	// 将坐标从模型输入尺寸转换回原始图像尺寸
 	var scaleX, scaleY float32
 	if y.config.InputWidth > 0 && y.config.InputHeight > 0 {
@@ -500,56 +704,127 @@ func (y *YOLO) DetectImage(imagePath string) ([]Detection, error) {
 		detections[i].Box[3] *= scaleY // y2
 	}
 
-	// 应用非极大抑制
-	threshold := float32(0.5) // 默认值
-	if y.runtimeConfig != nil {
-		threshold = y.runtimeConfig.IOUThreshold
+	// 应用非极大抑制（或加权框融合）
+	keep := y.suppressOverlaps(detections)
+
+	// 测试时增强和后处理
+	return y.finalizeDetections(img, keep)
+}
+
+// DetectImageReader 从io.Reader解码图像并检测（如HTTP上传），无需写入临时文件
+func (y *YOLO) DetectImageReader(r io.Reader) ([]Detection, error) {
+	img, err := imaging.Decode(r, imaging.AutoOrientation(y.config.AutoOrient))
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无法解码图像: %w", ErrInvalidInput, err)
 	}
-	keep := y.nonMaxSuppression(detections, threshold)
+	return y.detectImage(img)
+}
 
-	return keep, nil
+// DetectImageImage 检测内存中的图像
+func (y *YOLO) DetectImageImage(img image.Image) ([]Detection, error) {
+	if img == nil {
+		return nil, fmt.Errorf("图像为空")
+	}
+	return y.detectImage(img)
+}
+
+// DetectImageRaw 返回模型对图片的原始输出数据和形状（解析前），用于调试自定义模型的输出格式
+// 输出张量由ONNX Runtime按模型实际形状分配，不依赖已知输出形状
+func (y *YOLO) DetectImageRaw(imagePath string) ([]float32, []int64, error) {
+	inputData, err := y.preprocessImage(imagePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("图像预处理失败: %w", err)
+	}
+
+	inputShape := ort.NewShape(1, 3, int64(y.config.InputSize), int64(y.config.InputSize))
+	if y.config.InputWidth > 0 && y.config.InputHeight > 0 {
+		inputShape = ort.NewShape(1, 3, int64(y.config.InputHeight), int64(y.config.InputWidth))
+	}
+	inputTensor, err := ort.NewTensor(inputShape, inputData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: 无法创建输入张量: %w", ErrInference, err)
+	}
+	defer inputTensor.Destroy()
+
+	outputs := []ort.Value{nil}
+	if err := y.session.Run([]ort.Value{inputTensor}, outputs); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInference, err)
+	}
+	defer outputs[0].Destroy()
+
+	tensor, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		return nil, nil, fmt.Errorf("不支持的输出张量类型: %T", outputs[0])
+	}
+
+	shape := append([]int64(nil), tensor.GetShape()...)
+	data := append([]float32(nil), tensor.GetData()...)
+	fmt.Printf("🔍 模型原始输出形状: %v (%d个值)\n", shape, len(data))
+	return data, shape, nil
 }
 
 // DetectAndSave 检测图片并保存结果
 func (y *YOLO) DetectAndSave(imagePath, outputPath string) ([]Detection, error) {
+	imgWithBoxes, detections, err := y.DetectAndAnnotate(imagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// 保存图片
+	err = imaging.Save(imgWithBoxes, outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("保存图片失败: %v", err)
+	}
+
+	return detections, nil
+}
+
+// DetectAndAnnotate 检测图片并返回绘制了检测结果的图像（不写入磁盘）
+func (y *YOLO) DetectAndAnnotate(imagePath string) (image.Image, []Detection, error) {
 	// 如果没有设置运行时配置，使用默认配置
 	if y.runtimeConfig == nil {
-		y.runtimeConfig = DefaultDetectionOptions()
+		y.runtimeConfig = y.resolveOptions(nil)
 	}
 
 	// 检测图片
 	detections, err := y.DetectImage(imagePath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// 读取原始图片
-	img, err := imaging.Open(imagePath)
+	img, err := y.openImage(imagePath)
 	if err != nil {
-		return nil, fmt.Errorf("无法打开图片: %v", err)
+		return nil, nil, fmt.Errorf("无法打开图片: %v", err)
 	}
 
 	// 在图片上绘制检测框
-	imgWithBoxes := y.drawDetectionsOnImage(img, detections)
+	return y.drawDetectionsOnImage(img, detections), detections, nil
+}
 
-	// 保存图片
-	err = imaging.Save(imgWithBoxes, outputPath)
+// DetectAndAnnotateReader 从io.Reader解码图像，检测并返回绘制了检测框的图像（如HTTP上传）
+func (y *YOLO) DetectAndAnnotateReader(r io.Reader) (image.Image, []Detection, error) {
+	img, err := imaging.Decode(r, imaging.AutoOrientation(y.config.AutoOrient))
 	if err != nil {
-		return nil, fmt.Errorf("保存图片失败: %v", err)
+		return nil, nil, fmt.Errorf("%w: 无法解码图像: %w", ErrInvalidInput, err)
 	}
 
-	return detections, nil
+	detections, err := y.detectImage(img)
+	if err != nil {
+		return nil, nil, err
+	}
+	return y.drawDetectionsOnImage(img, detections), detections, nil
 }
 
 // DetectVideo 检测视频文件（MP4等）
 func (y *YOLO) DetectVideo(inputPath string, showLive ...bool) ([]VideoDetectionResult, error) {
 	// 如果没有设置运行时配置，使用默认配置
 	if y.runtimeConfig == nil {
-		y.runtimeConfig = DefaultDetectionOptions()
+		y.runtimeConfig = y.resolveOptions(nil)
 	}
 
 	if !isVideoFile(inputPath) {
-		return nil, fmt.Errorf("不支持的文件格式，请使用MP4等视频文件")
+		return nil, fmt.Errorf("%w，请使用MP4等视频文件", ErrUnsupportedFormat)
 	}
 
 	// 使用Vidio处理视频文件
@@ -568,11 +843,11 @@ func (y *YOLO) DetectVideo(inputPath string, showLive ...bool) ([]VideoDetection
 func (y *YOLO) DetectVideoAndSave(inputPath, outputPath string, showLive ...bool) error {
 	// 如果没有设置运行时配置，使用默认配置
 	if y.runtimeConfig == nil {
-		y.runtimeConfig = DefaultDetectionOptions()
+		y.runtimeConfig = y.resolveOptions(nil)
 	}
 
 	if !isVideoFile(inputPath) {
-		return fmt.Errorf("不支持的文件格式，请使用MP4等视频文件")
+		return fmt.Errorf("%w，请使用MP4等视频文件", ErrUnsupportedFormat)
 	}
 
 	// 使用Vidio处理视频文件
@@ -641,28 +916,31 @@ func DefaultVideoOptions() VideoOptions {
 	}
 }
 
-// 预处理图像
-func (y *YOLO) preprocessImage(imagePath string) ([]float32, error) {
-	// 打开图像
-	file, err := os.Open(imagePath)
+// openImage 打开图像文件，启用AutoOrient时按EXIF方向信息自动旋转
+func (y *YOLO) openImage(imagePath string) (image.Image, error) {
+	img, err := imaging.Open(imagePath, imaging.AutoOrientation(y.config.AutoOrient))
 	if err != nil {
-		return nil, fmt.Errorf("无法打开图像文件 '%s': %v", imagePath, err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
 	}
-	defer file.Close()
+	return img, nil
+}
 
-	img, _, err := image.Decode(file)
+// 预处理图像
+func (y *YOLO) preprocessImage(imagePath string) ([]float32, error) {
+	// 打开图像
+	img, err := y.openImage(imagePath)
 	if err != nil {
-		return nil, fmt.Errorf("无法解码图像: %v", err)
+		return nil, fmt.Errorf("无法打开图像文件 '%s': %w", imagePath, err)
 	}
 
 	// 根据配置调整大小 - 直接缩放
 	var resized image.Image
 	if y.config.InputWidth > 0 && y.config.InputHeight > 0 {
 		// 使用自定义的宽度和高度 - 直接缩放
-		resized = imaging.Resize(img, y.config.InputWidth, y.config.InputHeight, imaging.Lanczos)
+		resized = y.resizeForInput(img, y.config.InputWidth, y.config.InputHeight)
 	} else {
 		// 使用正方形输入尺寸 - 直接缩放
-		resized = imaging.Resize(img, y.config.InputSize, y.config.InputSize, imaging.Lanczos)
+		resized = y.resizeForInput(img, y.config.InputSize, y.config.InputSize)
 	}
 
 	// 转换为RGB并归一化
@@ -675,6 +953,7 @@ func (y *YOLO) preprocessImage(imagePath string) ([]float32, error) {
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
 			r, g, b, _ := resized.At(x, y).RGBA()
+			// RGBA()总是返回16位分量，>>8 对8位和16位（如NRGBA64）图像都正确
 			// 归一化到 [0, 1]
 			data[0*height*width+y*width+x] = float32(r>>8) / 255.0 // R通道
 			data[1*height*width+y*width+x] = float32(g>>8) / 255.0 // G通道
@@ -685,6 +964,126 @@ func (y *YOLO) preprocessImage(imagePath string) ([]float32, error) {
 	return data, nil
 }
 
+// inferDetections 创建输入张量、运行推理并解析输出，返回模型输入尺寸坐标下的检测结果（NMS前）
+// verbose 为true时输出形状探测日志（单张图片检测时使用，视频逐帧检测时关闭）
+func (y *YOLO) inferDetections(inputData []float32, verbose bool, timings *Timings) ([]Detection, error) {
+	// 创建输入张量
+	var inputShape ort.Shape
+	if y.config.InputWidth > 0 && y.config.InputHeight > 0 {
+		// 使用自定义的宽度和高度
+		inputShape = ort.NewShape(1, 3, int64(y.config.InputHeight), int64(y.config.InputWidth))
+	} else {
+		// 使用正方形输入尺寸
+		inputShape = ort.NewShape(1, 3, int64(y.config.InputSize), int64(y.config.InputSize))
+	}
+	inputTensor, err := ort.NewTensor(inputShape, inputData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无法创建输入张量: %w", ErrInference, err)
+	}
+
+	// 输出由ONNX Runtime按模型实际形状分配（兼容特征在后的导出、YOLOv5和自定义类别数的模型）
+	// 推理超时后张量仍被后台推理使用，改由runSession在推理结束后释放
+	outputs := []ort.Value{nil}
+	abandoned := false
+	defer func() {
+		if abandoned {
+			return
+		}
+		inputTensor.Destroy()
+		destroyValues(outputs)
+	}()
+
+	// 基于锚框的多尺度原始网格输出单独解码
+	if y.config.Anchors != nil {
+		detections, err := y.inferAnchorGrid(inputTensor, timings)
+		abandoned = errors.Is(err, ErrInferenceTimeout)
+		return detections, err
+	}
+
+	// 运行推理
+	runStart := time.Now()
+	err = y.runSession([]ort.Value{inputTensor}, outputs)
+	timings.addInference(time.Since(runStart))
+	if err != nil {
+		if errors.Is(err, ErrInferenceTimeout) {
+			abandoned = true
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInference, err)
+	}
+
+	outputTensor, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		return nil, fmt.Errorf("%w: 不支持的输出张量类型: %T", ErrInference, outputs[0])
+	}
+
+	// 获取实际的输出形状并更新模型信息（输出张量随后释放，复制一份形状）
+	actualOutputShape := append([]int64(nil), outputTensor.GetShape()...)
+	y.recordOutputShape(actualOutputShape, verbose)
+
+	// 解析检测结果
+	detections := y.parseDetections(outputTensor.GetData(), actualOutputShape)
+	if err := y.classCountError(); err != nil {
+		return nil, err
+	}
+	return detections, nil
+}
+
+// recordOutputShape 记录推理得到的实际输出形状，只在与已知形状不同时写入（批量检测会并发调用）
+func (y *YOLO) recordOutputShape(shape []int64, verbose bool) {
+	y.outputShapeMu.Lock()
+	defer y.outputShapeMu.Unlock()
+
+	if slices.Equal(y.modelOutputShape, shape) {
+		return
+	}
+	if verbose && (len(y.modelOutputShape) == 0 || containsDynamicDimension(y.modelOutputShape)) {
+		fmt.Printf("✅ 自动检测到模型实际输出形状: %v\n", shape)
+	}
+	y.modelOutputShape = shape
+}
+
+// checkClassCount 首次推理时比较模型输出的类别数与已加载的类别列表，不一致时输出明确的警告
+func (y *YOLO) checkClassCount(numClasses int) {
+	y.classCountOnce.Do(func() {
+		if numClasses == len(globalClasses) {
+			return
+		}
+		y.classCountErr = fmt.Errorf("%w: 模型输出 %d 个类别，但类别配置文件列出 %d 个，请检查data.yaml是否与模型匹配",
+			ErrClassCountMismatch, numClasses, len(globalClasses))
+		fmt.Printf("⚠️  %v\n", y.classCountErr)
+	})
+}
+
+// declaredClassCount 根据模型声明的输出形状推算类别数（锚框模型或动态维度时返回false）
+func (y *YOLO) declaredClassCount() (int, bool) {
+	shape := y.modelOutputShape
+	if y.config.Anchors != nil || len(shape) != 3 || shape[1] <= 0 || shape[2] <= 0 {
+		return 0, false
+	}
+
+	featuresLast := y.outputFeaturesLast(shape)
+	numFeatures := int(shape[1])
+	if featuresLast {
+		numFeatures = int(shape[2])
+	}
+
+	// 与parseDetections一致：特征在后且多出目标置信度一列时按YOLOv5输出处理
+	numClasses := numFeatures - 4
+	if featuresLast && numFeatures-5 == len(globalClasses) {
+		numClasses = numFeatures - 5
+	}
+	return numClasses, numClasses > 0
+}
+
+// classCountError 严格类别模式下返回类别数不一致错误
+func (y *YOLO) classCountError() error {
+	if y.config.StrictClasses {
+		return y.classCountErr
+	}
+	return nil
+}
+
 // 解析检测结果
 func (y *YOLO) parseDetections(outputData []float32, outputShape []int64) []Detection {
 	if len(outputShape) != 3 || outputShape[0] != 1 {
@@ -692,32 +1091,56 @@ func (y *YOLO) parseDetections(outputData []float32, outputShape []int64) []Dete
 		return nil
 	}
 
+	// 确定输出布局：[batch, features, detections] 或 [batch, detections, features]
+	featuresLast := y.outputFeaturesLast(outputShape)
+
 	numDetections := int(outputShape[2]) // 例如: 8400
 	numFeatures := int(outputShape[1])   // 例如: 84, 85, 等
-	numClasses := numFeatures - 4        // 动态计算类别数量 (总特征数 - 4个坐标)
+	if featuresLast {
+		numDetections, numFeatures = numFeatures, numDetections
+	}
+
+	// YOLOv5风格输出 [1, 25200, 85]：每行一个候选框，包含目标置信度（objectness）
+	if featuresLast && numFeatures-5 == len(globalClasses) {
+		return y.parseDetectionsV5(outputData, numDetections, numFeatures)
+	}
+
+	// value 返回第i个检测框的第f个特征
+	value := func(i, f int) float32 {
+		if featuresLast {
+			return outputData[i*numFeatures+f]
+		}
+		return outputData[f*numDetections+i]
+	}
+
+	numClasses := numFeatures - 4 // 动态计算类别数量 (总特征数 - 4个坐标)
 
 	if numClasses <= 0 {
 		fmt.Printf("⚠️  无效的类别数量: %d (特征数: %d)\n", numClasses, numFeatures)
 		return nil
 	}
 
-	fmt.Printf("📊 解析输出: %d个检测框, %d个特征, %d个类别\n", numDetections, numFeatures, numClasses)
+	y.verbosef("📊 解析输出: %d个检测框, %d个特征, %d个类别\n", numDetections, numFeatures, numClasses)
+	y.checkClassCount(numClasses)
 
 	var detections []Detection
+	confThreshold := y.confThreshold()
+	temperature := y.scoreTemperature()
+	ignored := y.ignoredClasses()
 
 	// 解析检测结果
 	for i := 0; i < numDetections; i++ {
-		// 对于格式 [batch, features, detections]，访问第i个检测的所有特征
-		cx := outputData[0*numFeatures*numDetections+0*numDetections+i]
-		cy := outputData[0*numFeatures*numDetections+1*numDetections+i]
-		w := outputData[0*numFeatures*numDetections+2*numDetections+i]
-		h := outputData[0*numFeatures*numDetections+3*numDetections+i]
+		// 访问第i个检测的所有特征
+		cx := value(i, 0)
+		cy := value(i, 1)
+		w := value(i, 2)
+		h := value(i, 3)
 
 		// 找到最大的类别概率
 		var bestScore float32 = 0
 		bestID := 0
 		for classIdx := 0; classIdx < numClasses; classIdx++ {
-			score := outputData[0*numFeatures*numDetections+(4+classIdx)*numDetections+i]
+			score := calibrateScore(value(i, 4+classIdx), temperature)
 			if score > bestScore {
 				bestScore = score
 				bestID = classIdx
@@ -725,11 +1148,6 @@ func (y *YOLO) parseDetections(outputData []float32, outputShape []int64) []Dete
 		}
 
 		// 使用配置的置信度阈值
-		confThreshold := float32(0.5) // 默认值
-		if y.runtimeConfig != nil {
-			confThreshold = y.runtimeConfig.ConfThreshold
-		}
-
 		if bestScore < confThreshold {
 			continue
 		}
@@ -744,20 +1162,186 @@ func (y *YOLO) parseDetections(outputData []float32, outputShape []int64) []Dete
 		if bestID < len(globalClasses) {
 			className = globalClasses[bestID]
 		}
+		if ignored[className] {
+			continue
+		}
+
+		detections = append(detections, Detection{
+			Box:     [4]float32{x1, y1, x2, y2},
+			Score:   bestScore,
+			ClassID: bestID,
+			Class:   className,
+			TopClasses: y.topClasses(numClasses, func(classIdx int) float32 {
+				return calibrateScore(value(i, 4+classIdx), temperature)
+			}),
+		})
+	}
+
+	return detections
+}
+
+// outputFeaturesLast 判断输出布局是否为 [batch, detections, features]
+// 未指定布局时自动判断：候选框数量（如8400）总是远大于特征数（如84）
+func (y *YOLO) outputFeaturesLast(outputShape []int64) bool {
+	switch y.config.OutputLayout {
+	case OutputLayoutFeaturesFirst:
+		return false
+	case OutputLayoutFeaturesLast:
+		return true
+	default:
+		return outputShape[1] > outputShape[2]
+	}
+}
+
+// parseDetectionsV5 解析YOLOv5风格输出 [1, numDetections, 5+类别数]
+// 每行为 cx, cy, w, h, objectness, 各类别概率；最终分数 = objectness × 类别概率
+func (y *YOLO) parseDetectionsV5(outputData []float32, numDetections, numFeatures int) []Detection {
+	numClasses := numFeatures - 5
+	y.verbosef("📊 解析YOLOv5输出: %d个检测框, %d个特征, %d个类别\n", numDetections, numFeatures, numClasses)
+
+	var detections []Detection
+	confThreshold := y.confThreshold()
+	objThreshold := y.objectnessThreshold()
+	temperature := y.scoreTemperature()
+	ignored := y.ignoredClasses()
+
+	for i := 0; i < numDetections; i++ {
+		row := outputData[i*numFeatures : (i+1)*numFeatures]
+
+		// 先按目标置信度过滤，再与类别概率相乘
+		objectness := row[4]
+		if objectness < objThreshold {
+			continue
+		}
+
+		var bestScore float32 = 0
+		bestID := 0
+		for classIdx := 0; classIdx < numClasses; classIdx++ {
+			if score := calibrateScore(row[5+classIdx], temperature); score > bestScore {
+				bestScore = score
+				bestID = classIdx
+			}
+		}
+
+		score := objectness * bestScore
+		if score < confThreshold {
+			continue
+		}
+
+		cx, cy, w, h := row[0], row[1], row[2], row[3]
+
+		className := "unknown"
+		if bestID < len(globalClasses) {
+			className = globalClasses[bestID]
+		}
+		if ignored[className] {
+			continue
+		}
 
 		detections = append(detections, Detection{
-			Box:     [4]float32{x1, y1, x2, y2},
-			Score:   bestScore,
+			Box:     [4]float32{cx - w/2.0, cy - h/2.0, cx + w/2.0, cy + h/2.0},
+			Score:   score,
 			ClassID: bestID,
 			Class:   className,
+			TopClasses: y.topClasses(numClasses, func(classIdx int) float32 {
+				return objectness * calibrateScore(row[5+classIdx], temperature)
+			}),
 		})
 	}
 
 	return detections
 }
 
+// topClasses 返回分数最高的K个类别（K由WithTopClasses设置，未启用时返回nil）
+func (y *YOLO) topClasses(numClasses int, score func(classIdx int) float32) []ClassScore {
+	if y.runtimeConfig == nil || y.runtimeConfig.TopK <= 0 {
+		return nil
+	}
+
+	scores := make([]ClassScore, numClasses)
+	for classIdx := range scores {
+		className := "unknown"
+		if classIdx < len(globalClasses) {
+			className = globalClasses[classIdx]
+		}
+		scores[classIdx] = ClassScore{ClassID: classIdx, Class: className, Score: score(classIdx)}
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+
+	if y.runtimeConfig.TopK < len(scores) {
+		scores = scores[:y.runtimeConfig.TopK]
+	}
+	return scores
+}
+
+// objectnessThreshold 当前生效的目标置信度阈值（仅YOLOv5风格输出使用，未配置时不过滤）
+func (y *YOLO) objectnessThreshold() float32 {
+	if y.runtimeConfig != nil && y.runtimeConfig.ObjectnessThreshold > 0 {
+		return y.runtimeConfig.ObjectnessThreshold
+	}
+	return 0
+}
+
+// ignoredClasses 当前生效的忽略类别集合（未配置时为nil）
+func (y *YOLO) ignoredClasses() map[string]bool {
+	if y.runtimeConfig == nil || len(y.runtimeConfig.IgnoreClasses) == 0 {
+		return nil
+	}
+	ignored := make(map[string]bool, len(y.runtimeConfig.IgnoreClasses))
+	for _, class := range y.runtimeConfig.IgnoreClasses {
+		ignored[class] = true
+	}
+	return ignored
+}
+
+// scoreTemperature 当前生效的类别分数温度（0或1表示不缩放）
+func (y *YOLO) scoreTemperature() float32 {
+	if y.runtimeConfig != nil && y.runtimeConfig.ScoreTemperature > 0 {
+		return y.runtimeConfig.ScoreTemperature
+	}
+	return 1
+}
+
+// calibrateScore 对概率分数做温度缩放：sigmoid(logit(p) / t)，不改变类别间的排序
+func calibrateScore(p, t float32) float32 {
+	if t == 1 || t <= 0 {
+		return p
+	}
+	const eps = 1e-7
+	if p < eps {
+		p = eps
+	} else if p > 1-eps {
+		p = 1 - eps
+	}
+	logit := math.Log(float64(p) / float64(1-p))
+	return float32(1 / (1 + math.Exp(-logit/float64(t))))
+}
+
+// confThreshold 当前生效的置信度阈值（未配置时使用默认检测选项）
+func (y *YOLO) confThreshold() float32 {
+	if y.runtimeConfig != nil && y.runtimeConfig.ConfThreshold > 0 {
+		return y.runtimeConfig.ConfThreshold
+	}
+	return DefaultConfThreshold
+}
+
+// iouThreshold 当前生效的NMS IOU阈值（未配置时使用默认检测选项）
+func (y *YOLO) iouThreshold() float32 {
+	if y.runtimeConfig != nil && y.runtimeConfig.IOUThreshold > 0 {
+		return y.runtimeConfig.IOUThreshold
+	}
+	return DefaultIOUThreshold
+}
+
 // IOU计算
 func (y *YOLO) iou(box1, box2 [4]float32) float32 {
+	return boxIOU(box1, box2)
+}
+
+// boxIOU 计算两个 x1, y1, x2, y2 格式检测框的IOU
+func boxIOU(box1, box2 [4]float32) float32 {
 	x1Min, y1Min, x1Max, y1Max := box1[0], box1[1], box1[2], box1[3]
 	x2Min, y2Min, x2Max, y2Max := box2[0], box2[1], box2[2], box2[3]
 
@@ -773,6 +1357,101 @@ func (y *YOLO) iou(box1, box2 [4]float32) float32 {
 	return interArea / (area1 + area2 - interArea + 1e-6)
 }
 
+// finalizeDetections 对NMS后的结果执行测试时增强和用户后处理器
+func (y *YOLO) finalizeDetections(img image.Image, detections []Detection) ([]Detection, error) {
+	detections, err := y.applyTTA(img, detections)
+	if err != nil {
+		return nil, err
+	}
+
+	if y.runtimeConfig != nil && (y.runtimeConfig.ClampBoxes || y.runtimeConfig.DropOffscreen) {
+		bounds := img.Bounds()
+		detections = clampDetections(detections, float32(bounds.Dx()), float32(bounds.Dy()),
+			y.runtimeConfig.ClampBoxes, y.runtimeConfig.DropOffscreen)
+	}
+
+	// 类别映射只在这里执行一次（各检测路径都经过finalizeDetections），避免链式映射重复生效
+	detections = y.applyClassMapping(detections)
+
+	if y.runtimeConfig != nil && y.runtimeConfig.PostProcessor != nil {
+		y.runPostProcessor(img, detections)
+	}
+	return detections, nil
+}
+
+// filterAspectRatio 丢弃宽高比（宽/高）超出配置范围的检测框，范围边界<=0表示不限制
+func (y *YOLO) filterAspectRatio(detections []Detection) []Detection {
+	if y.runtimeConfig == nil || (y.runtimeConfig.MinAspectRatio <= 0 && y.runtimeConfig.MaxAspectRatio <= 0) {
+		return detections
+	}
+
+	minRatio, maxRatio := y.runtimeConfig.MinAspectRatio, y.runtimeConfig.MaxAspectRatio
+	result := detections[:0]
+	for _, det := range detections {
+		width := det.Box[2] - det.Box[0]
+		height := det.Box[3] - det.Box[1]
+		if width <= 0 || height <= 0 {
+			continue
+		}
+		ratio := width / height
+		if (minRatio > 0 && ratio < minRatio) || (maxRatio > 0 && ratio > maxRatio) {
+			continue
+		}
+		result = append(result, det)
+	}
+	return result
+}
+
+// clampDetections 将检测框裁剪到图像范围内，dropOffscreen时丢弃中心点在画面外的检测结果
+func clampDetections(detections []Detection, width, height float32, clamp, dropOffscreen bool) []Detection {
+	result := detections[:0]
+	for _, det := range detections {
+		if dropOffscreen {
+			cx := (det.Box[0] + det.Box[2]) / 2
+			cy := (det.Box[1] + det.Box[3]) / 2
+			if cx < 0 || cy < 0 || cx >= width || cy >= height {
+				continue
+			}
+		}
+		if clamp {
+			det.Box[0] = minFloat32(max(0, det.Box[0]), width)
+			det.Box[1] = minFloat32(max(0, det.Box[1]), height)
+			det.Box[2] = minFloat32(max(0, det.Box[2]), width)
+			det.Box[3] = minFloat32(max(0, det.Box[3]), height)
+		}
+		result = append(result, det)
+	}
+	return result
+}
+
+// runPostProcessor 对每个检测结果裁剪目标区域并调用后处理器（如二级分类器）
+func (y *YOLO) runPostProcessor(img image.Image, detections []Detection) {
+	bounds := img.Bounds()
+	for i := range detections {
+		box := detections[i].Box
+		rect := image.Rect(int(box[0]), int(box[1]), int(box[2]), int(box[3])).
+			Add(bounds.Min).Intersect(bounds)
+		if rect.Empty() {
+			continue
+		}
+		y.runtimeConfig.PostProcessor(imaging.Crop(img, rect), &detections[i])
+	}
+}
+
+// suppressOverlaps 按运行时配置对重叠框执行自定义NMS、加权框融合（WBF）或内置NMS
+// 执行前先按宽高比范围过滤（坐标已转换为原图尺寸）
+func (y *YOLO) suppressOverlaps(detections []Detection) []Detection {
+	detections = y.filterAspectRatio(detections)
+
+	if y.runtimeConfig != nil && y.runtimeConfig.NMSFunc != nil {
+		return y.runtimeConfig.NMSFunc(detections, y.iouThreshold())
+	}
+	if y.runtimeConfig != nil && y.runtimeConfig.UseWBF {
+		return weightedBoxesFusion(detections, y.iouThreshold())
+	}
+	return y.nonMaxSuppression(detections, y.iouThreshold())
+}
+
 // 非极大抑制
 func (y *YOLO) nonMaxSuppression(detections []Detection, iouThreshold float32) []Detection {
 	if len(detections) == 0 {
@@ -807,12 +1486,14 @@ func (y *YOLO) nonMaxSuppression(detections []Detection, iouThreshold float32) [
 // 画检测框
 func (y *YOLO) drawBBox(img draw.Image, bbox [4]float32, lineColor color.Color) {
 	bounds := img.Bounds()
-	width, height := bounds.Max.X, bounds.Max.Y
+	minX, minY := float32(bounds.Min.X), float32(bounds.Min.Y)
+	maxX, maxY := float32(bounds.Max.X), float32(bounds.Max.Y)
 
-	x1 := int(max(0, minFloat32(float32(width-1), bbox[0])))
-	y1 := int(max(0, minFloat32(float32(height-1), bbox[1])))
-	x2 := int(max(0, minFloat32(float32(width-1), bbox[2])))
-	y2 := int(max(0, minFloat32(float32(height-1), bbox[3])))
+	// 限制在图像范围内（浮点坐标，边缘抗锯齿）
+	x1 := max(minX, minFloat32(maxX, bbox[0]))
+	y1 := max(minY, minFloat32(maxY, bbox[1]))
+	x2 := max(minX, minFloat32(maxX, bbox[2]))
+	y2 := max(minY, minFloat32(maxY, bbox[3]))
 
 	// 获取线条宽度
 	lineWidth := 1
@@ -820,42 +1501,29 @@ func (y *YOLO) drawBBox(img draw.Image, bbox [4]float32, lineColor color.Color)
 		lineWidth = y.runtimeConfig.LineWidth
 	}
 
-	// 画矩形框（支持自定义线条宽度）
-	for i := 0; i < lineWidth; i++ {
-		// 上边和下边
-		for x := x1; x <= x2; x++ {
-			if y1+i < height {
-				img.Set(x, y1+i, lineColor) // 上边
-			}
-			if y2-i >= 0 {
-				img.Set(x, y2-i, lineColor) // 下边
-			}
-		}
-		// 左边和右边
-		for y := y1; y <= y2; y++ {
-			if x1+i < width {
-				img.Set(x1+i, y, lineColor) // 左边
-			}
-			if x2-i >= 0 {
-				img.Set(x2-i, y, lineColor) // 右边
-			}
-		}
+	style := BoxStyleFull
+	var radius float32
+	var fillAlpha uint8
+	if y.runtimeConfig != nil {
+		style = y.runtimeConfig.BoxStyle
+		radius = y.runtimeConfig.BoxCornerRadius
+		fillAlpha = y.runtimeConfig.BoxFillAlpha
+	}
+
+	// 先半透明填充，再绘制边框
+	if fillAlpha > 0 {
+		fillBox(img, x1, y1, x2, y2, radius, lineColor, fillAlpha)
 	}
+	strokeBox(img, x1, y1, x2, y2, float32(lineWidth), radius, style, lineColor)
 }
 
 // 绘制检测结果
 func (y *YOLO) drawDetections(imagePath, outputPath string, detections []Detection) error {
 	// 重新加载图像
-	file, err := os.Open(imagePath)
+	img, err := y.openImage(imagePath)
 	if err != nil {
 		return fmt.Errorf("无法重新打开图像文件: %v", err)
 	}
-	defer file.Close()
-
-	img, _, err := image.Decode(file)
-	if err != nil {
-		return fmt.Errorf("无法解码图像: %v", err)
-	}
 
 	// 转换为可绘制的图像
 	bounds := img.Bounds()
@@ -894,8 +1562,7 @@ func (y *YOLO) drawDetections(imagePath, outputPath string, detections []Detecti
 
 		if drawLabels {
 			// 绘制标签文本
-			label := fmt.Sprintf("%s %.2f", detection.Class, detection.Score)
-			y.drawLabel(origImg, label, int(x1), int(y1-20)) // 在框上方绘制标签
+			y.drawLabel(origImg, y.formatLabel(detection), [4]float32{x1, y1, x2, y2})
 		}
 	}
 
@@ -957,22 +1624,40 @@ func (y *YOLO) drawDetectionsOnImage(img image.Image, detections []Detection) im
 		}
 
 		if drawBoxes {
-			// 画检测框
-			y.drawBBox(origImg, [4]float32{x1, y1, x2, y2}, boxColor)
+			// 画检测框（按类别颜色）
+			y.drawBBox(origImg, [4]float32{x1, y1, x2, y2}, y.classColor(detection.Class, boxColor))
 		}
 
 		if drawLabels {
-			// 绘制标签文本
-			label := fmt.Sprintf("%s %.2f", detection.Class, detection.Score)
-			y.drawLabel(origImg, label, int(x1), int(y1-20)) // 在框上方绘制标签
+			// 绘制标签文本（有跟踪ID时显示在类别前）
+			y.drawLabel(origImg, y.formatLabel(detection), [4]float32{x1, y1, x2, y2})
+		}
+	}
+
+	// 绘制跟踪轨迹（轨迹在检测流水线中逐帧更新，绘制只读取）
+	if y.runtimeConfig != nil && y.runtimeConfig.TrailLength > 0 {
+		y.drawTrackTrails(origImg, boxColor)
+	}
+
+	// 绘制区域轮廓和计数（计数在检测流水线中逐帧更新）
+	if y.runtimeConfig != nil && y.runtimeConfig.Zones != nil {
+		labelColor := color.RGBA{255, 255, 255, 255}
+		if parsedColor := y.parseColor(y.runtimeConfig.LabelColor); parsedColor != nil {
+			labelColor = *parsedColor
 		}
+		y.runtimeConfig.Zones.Draw(origImg, boxColor, labelColor)
+	}
+
+	// 绘制类别图例
+	if y.runtimeConfig != nil && y.runtimeConfig.ShowLegend {
+		y.drawLegend(origImg, detections, boxColor)
 	}
 
 	return origImg
 }
 
 // drawLabel 绘制标签文本
-func (y *YOLO) drawLabel(img *image.RGBA, label string, x, yPos int) {
+func (y *YOLO) drawLabel(img *image.RGBA, label string, box [4]float32) {
 	bounds := img.Bounds()
 
 	// 设置字体和尺寸（支持自定义字体大小）
@@ -1010,6 +1695,7 @@ func (y *YOLO) drawLabel(img *image.RGBA, label string, x, yPos int) {
 	padding := 4
 
 	// 确保标签在图像范围内
+	x := int(box[0])
 	if x < 0 {
 		x = 0
 	}
@@ -1017,11 +1703,31 @@ func (y *YOLO) drawLabel(img *image.RGBA, label string, x, yPos int) {
 		x = bounds.Max.X - textWidth - padding*2
 	}
 
-	// 如果标签会超出上边界，就画在框下方
-	if yPos < textHeight+padding {
-		yPos = yPos + 30 // 画在框下方
+	// 按配置的标签位置计算文本顶部坐标
+	position := LabelPositionAuto
+	if y.runtimeConfig != nil {
+		position = y.runtimeConfig.LabelPosition
+	}
+
+	var yPos int
+	switch position {
+	case LabelPositionAbove:
+		yPos = int(box[1]) - textHeight - padding
+	case LabelPositionBelow:
+		yPos = int(box[3]) + padding
+	case LabelPositionInsideTop:
+		yPos = int(box[1]) + padding
+	default:
+		// 在框上方绘制，如果标签会超出上边界，就画在框下方
+		yPos = int(box[1]) - 20
+		if yPos < textHeight+padding {
+			yPos = yPos + 30
+		}
 	}
 
+	if yPos < 0 {
+		yPos = 0
+	}
 	if yPos > bounds.Max.Y-textHeight-padding {
 		yPos = bounds.Max.Y - textHeight - padding
 	}
@@ -1051,7 +1757,7 @@ func (y *YOLO) drawLabel(img *image.RGBA, label string, x, yPos int) {
 	d.DrawString(label)
 
 	// 调试信息
-	fmt.Printf("绘制标签: '%s' 在位置 (%d, %d)\n", label, x, yPos)
+	y.verbosef("绘制标签: '%s' 在位置 (%d, %d)\n", label, x, yPos)
 }
 
 // 辅助函数
@@ -1062,49 +1768,6 @@ func max(a, b float32) float32 {
 	return b
 }
 
-// resizeWithPadding 保持宽高比缩放图像并填充到目标尺寸
-func (y *YOLO) resizeWithPadding(img image.Image, targetWidth, targetHeight int) image.Image {
-	bounds := img.Bounds()
-	origWidth := float32(bounds.Dx())
-	origHeight := float32(bounds.Dy())
-
-	// 计算缩放比例，保持宽高比（与坐标转换逻辑保持一致）
-	scaleX := float32(targetWidth) / origWidth
-	scaleY := float32(targetHeight) / origHeight
-	scale := scaleX
-	if scaleY < scaleX {
-		scale = scaleY
-	}
-
-	// 计算缩放后的尺寸
-	scaledWidth := origWidth * scale
-	scaledHeight := origHeight * scale
-	newWidth := int(scaledWidth)
-	newHeight := int(scaledHeight)
-
-	// 缩放图像
-	resized := imaging.Resize(img, newWidth, newHeight, imaging.Lanczos)
-
-	// 创建目标尺寸的黑色背景
-	padded := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
-	// 默认为黑色背景 (0, 0, 0, 255)
-	for y := 0; y < targetHeight; y++ {
-		for x := 0; x < targetWidth; x++ {
-			padded.Set(x, y, color.RGBA{0, 0, 0, 255})
-		}
-	}
-
-	// 计算居中位置（与坐标转换逻辑保持一致）
-	offsetX := int((float32(targetWidth) - scaledWidth) / 2.0)
-	offsetY := int((float32(targetHeight) - scaledHeight) / 2.0)
-
-	// 将缩放后的图像粘贴到中心位置
-	draw.Draw(padded, image.Rect(offsetX, offsetY, offsetX+newWidth, offsetY+newHeight),
-		resized, image.Point{0, 0}, draw.Src)
-
-	return padded
-}
-
 // minFloat32函数已在video_simple.go中定义
 
 // parseColor 解析颜色字符串
@@ -1174,8 +1837,72 @@ func (y *YOLO) GetVideoProcessor() *VidioVideoProcessor {
 	return NewVidioVideoProcessor(y)
 }
 
+// GetVideoOptimization 获取检测器共享的视频优化实例
+// 所有通过该检测器创建的视频处理器默认复用此实例，配置一次即可在多次调用间生效
+func (y *YOLO) GetVideoOptimization() *VideoOptimization {
+	y.optimizationMu.Lock()
+	defer y.optimizationMu.Unlock()
+
+	if y.optimization == nil {
+		y.optimization = NewVideoOptimizationWithOptions(y.config.UseGPU, y.config.UseCUDA, y.config.CUDADeviceID, y.config.Parallelism)
+		y.applyOptimizationConfig()
+	}
+	return y.optimization
+}
+
+// applyOptimizationConfig 将YOLOConfig中的缩放算法、GC间隔和内存上限应用到共享的优化实例
+func (y *YOLO) applyOptimizationConfig() {
+	if y.optimization == nil {
+		return
+	}
+
+	y.optimization.SetResizeFilter(y.config.ResizeFilter)
+	y.optimization.SetMaxPreprocessDimension(y.config.MaxPreprocessDimension)
+
+	if y.config.GCInterval > 0 {
+		y.optimization.SetGCInterval(y.config.GCInterval)
+	}
+
+	if y.config.MemoryLimitMB > 0 {
+		// 仅调整内存上限，保留当前的协程数和CPU限制
+		y.optimization.resourceMonitor.mu.RLock()
+		maxGoroutines := y.optimization.resourceMonitor.maxGoroutines
+		maxCPU := y.optimization.resourceMonitor.maxCPU
+		y.optimization.resourceMonitor.mu.RUnlock()
+
+		y.optimization.AdjustPerformanceSettings(y.config.MemoryLimitMB, maxGoroutines, maxCPU)
+	}
+}
+
+// gpuProbe 缓存GPU探测结果，避免每次创建配置都重复探测CUDA/DirectML
+var (
+	gpuProbeOnce   sync.Once
+	gpuProbeResult bool
+)
+
 // IsGPUAvailable 检测GPU是否可用 - 基于用户成功案例的方法
+// 探测结果在ONNX Runtime初始化后只计算一次并缓存
 func IsGPUAvailable() bool {
+	// 运行时未初始化时无法创建会话选项，此时不缓存结果，初始化后再探测
+	if !ort.IsInitialized() {
+		return false
+	}
+
+	gpuProbeOnce.Do(func() {
+		gpuProbeResult = probeGPU()
+	})
+	return gpuProbeResult
+}
+
+// probeGPU 实际探测CUDA/DirectML执行提供者，追加提供者可能panic，使用recover保护
+func probeGPU() (available bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("⚠️  GPU探测发生panic: %v\n", r)
+			available = false
+		}
+	}()
+
 	// 创建临时会话选项来测试GPU支持
 	sessionOptions, err := ort.NewSessionOptions()
 	if err != nil {
@@ -1318,85 +2045,83 @@ func ConvertFramesToVideo(framesDir, outputPath string, fps int) string {
 func (y *YOLO) detectImage(img image.Image) ([]Detection, error) {
 	// 如果没有设置运行时配置，使用默认配置
 	if y.runtimeConfig == nil {
-		y.runtimeConfig = DefaultDetectionOptions()
+		y.runtimeConfig = y.resolveOptions(nil)
 	}
 
-	// 如果启用了GPU且优化模块可用，使用极致优化检测
-	if y.config.UseGPU && y.optimization != nil {
-		detections, err := y.optimization.OptimizedDetectImage(y, img)
-		if err != nil {
-			return nil, fmt.Errorf("GPU极致优化检测失败: %v", err)
-		}
+	var timings Timings
+	defer y.finishTimings(&timings, time.Now())
+
+	// 运动门控：画面基本静止时复用上一次检测结果
+	if detections, ok := y.motionGated(img); ok {
+		y.updateFrameState(detections)
 		return detections, nil
 	}
 
-	// 获取原始图像尺寸
-	originalBounds := img.Bounds()
-	originalWidth := float32(originalBounds.Dx())
-	originalHeight := float32(originalBounds.Dy())
-
-	// 预处理图像
-	inputData, err := y.preprocessImageFromMemory(img)
+	detections, err := y.runDetection(img, &timings)
 	if err != nil {
-		return nil, fmt.Errorf("图像预处理失败: %v", err)
+		return nil, err
 	}
+	y.recordMotionFrame(img, detections)
+	y.updateFrameState(detections)
+	return detections, nil
+}
 
-	// 创建输入张量
-	var inputShape ort.Shape
-	if y.config.InputWidth > 0 && y.config.InputHeight > 0 {
-		// 使用自定义的宽度和高度
-		inputShape = ort.NewShape(1, 3, int64(y.config.InputHeight), int64(y.config.InputWidth))
-	} else {
-		// 使用正方形尺寸
-		inputShape = ort.NewShape(1, 3, int64(y.config.InputSize), int64(y.config.InputSize))
-	}
-	inputTensor, err := ort.NewTensor(inputShape, inputData)
-	if err != nil {
-		return nil, fmt.Errorf("无法创建输入张量: %v", err)
+// updateFrameState 每处理一帧更新一次跨帧状态（跟踪轨迹、区域计数），绘制时只读取这些状态
+// 这样无检测结果的帧也会更新离开计数，同一帧绘制多次（预览和保存）也不会重复计数
+func (y *YOLO) updateFrameState(detections []Detection) {
+	if y.runtimeConfig.TrailLength <= 0 && y.runtimeConfig.Zones == nil {
+		return
 	}
-	defer inputTensor.Destroy()
-
-	// 创建输出张量（智能适配模型输出形状）
-	var outputShape ort.Shape
-	var outputDataSize int
+	// 轨迹和区域进出计数都依赖TrackID，先按帧间IOU匹配分配
+	y.tracker.assign(detections)
 
-	// 如果是第一次推理或者modelOutputShape包含动态维度，使用标准形状进行探测
-	if len(y.modelOutputShape) == 0 || containsDynamicDimension(y.modelOutputShape) {
-		// 使用标准YOLO输出形状进行第一次推理
-		outputShape = ort.NewShape(1, 84, 8400)
-		outputDataSize = 1 * 84 * 8400
-	} else {
-		// 使用已知的模型输出形状
-		outputShape = ort.NewShape(y.modelOutputShape...)
-		outputDataSize = 1
-		for _, dim := range y.modelOutputShape {
-			outputDataSize *= int(dim)
-		}
+	if y.runtimeConfig.TrailLength > 0 {
+		y.updateTrackTrails(detections, y.runtimeConfig.TrailLength)
 	}
+	if y.runtimeConfig.Zones != nil {
+		y.runtimeConfig.Zones.Update(VideoDetectionResult{Detections: detections})
+	}
+}
 
-	outputData := make([]float32, outputDataSize)
-	outputTensor, err := ort.NewTensor(outputShape, outputData)
+// runDetection 对内存图像执行完整检测（预处理、推理、后处理），各阶段耗时累加到timings（可为nil）
+func (y *YOLO) runDetection(img image.Image, timings *Timings) ([]Detection, error) {
+	// 设置了裁剪区域时只检测该区域
+	region, cropped, err := y.cropRegion(img)
 	if err != nil {
-		return nil, fmt.Errorf("无法创建输出张量: %v", err)
+		return nil, err
+	}
+	if cropped {
+		return y.detectCropRegion(img, region, timings)
 	}
-	defer outputTensor.Destroy()
 
-	// 运行推理
-	err = y.session.Run([]ort.Value{inputTensor}, []ort.Value{outputTensor})
+	// 如果启用了GPU且优化模块可用，使用极致优化检测
+	if y.config.UseGPU && y.optimization != nil {
+		detections, err := y.optimization.optimizedDetectImage(y, img, timings)
+		if err != nil {
+			return nil, fmt.Errorf("GPU极致优化检测失败: %w", err)
+		}
+		return y.finalizeDetections(img, detections)
+	}
+
+	// 获取原始图像尺寸
+	originalBounds := img.Bounds()
+	originalWidth := float32(originalBounds.Dx())
+	originalHeight := float32(originalBounds.Dy())
 
+	// 预处理图像
+	preStart := time.Now()
+	inputData, err := y.preprocessImageFromMemory(img)
 	if err != nil {
-		return nil, fmt.Errorf("推理失败: %v", err)
+		return nil, fmt.Errorf("图像预处理失败: %w", err)
 	}
+	timings.addPreprocess(time.Since(preStart))
 
-	// 获取实际的输出形状并更新模型信息
-	actualOutputShape := outputTensor.GetShape()
-	if len(y.modelOutputShape) == 0 || containsDynamicDimension(y.modelOutputShape) {
-		y.modelOutputShape = actualOutputShape
+	// 推理并解析检测结果（模型输入尺寸坐标）
+	detections, err := y.inferDetections(inputData, false, timings)
+	if err != nil {
+		return nil, err
 	}
 
-	// 解析检测结果
-	detections := y.parseDetections(outputTensor.GetData(), actualOutputShape)
-
 	// 将坐标从模型输入尺寸转换回原始图像尺寸
 	var scaleX, scaleY float32
 	if y.config.InputWidth > 0 && y.config.InputHeight > 0 {
@@ -1416,14 +2141,11 @@ func (y *YOLO) detectImage(img image.Image) ([]Detection, error) {
 		detections[i].Box[3] *= scaleY // y2
 	}
 
-	// 应用非极大抑制
-	threshold := float32(0.5) // 默认值
-	if y.runtimeConfig != nil {
-		threshold = y.runtimeConfig.IOUThreshold
-	}
-	keep := y.nonMaxSuppression(detections, threshold)
+	// 应用非极大抑制（或加权框融合）
+	keep := y.suppressOverlaps(detections)
 
-	return keep, nil
+	// 测试时增强和后处理
+	return y.finalizeDetections(img, keep)
 }
 
 // preprocessImageFromMemory 从内存图像预处理
@@ -1432,10 +2154,10 @@ func (y *YOLO) preprocessImageFromMemory(img image.Image) ([]float32, error) {
 	var resized image.Image
 	if y.config.InputWidth > 0 && y.config.InputHeight > 0 {
 		// 使用自定义的宽度和高度
-		resized = imaging.Resize(img, y.config.InputWidth, y.config.InputHeight, imaging.Lanczos)
+		resized = y.resizeForInput(img, y.config.InputWidth, y.config.InputHeight)
 	} else {
 		// 使用正方形输入尺寸
-		resized = imaging.Resize(img, y.config.InputSize, y.config.InputSize, imaging.Lanczos)
+		resized = y.resizeForInput(img, y.config.InputSize, y.config.InputSize)
 	}
 
 	// 转换为RGB并归一化
@@ -1448,6 +2170,7 @@ func (y *YOLO) preprocessImageFromMemory(img image.Image) ([]float32, error) {
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
 			r, g, b, _ := resized.At(x, y).RGBA()
+			// RGBA()总是返回16位分量，>>8 对8位和16位（如NRGBA64）图像都正确
 			// 归一化到 [0, 1]
 			data[0*height*width+y*width+x] = float32(r>>8) / 255.0 // R通道
 			data[1*height*width+y*width+x] = float32(g>>8) / 255.0 // G通道
@@ -1458,11 +2181,11 @@ func (y *YOLO) preprocessImageFromMemory(img image.Image) ([]float32, error) {
 	return data, nil
 }
 
-// detectWithPreprocessedData 使用预处理数据进行检测（优化版本）
-func (y *YOLO) detectWithPreprocessedData(inputData []float32, img image.Image) ([]Detection, error) {
+// detectWithPreprocessedData 使用预处理数据进行检测（优化版本），推理耗时累加到timings（可为nil）
+func (y *YOLO) detectWithPreprocessedData(inputData []float32, img image.Image, timings *Timings) ([]Detection, error) {
 	// 如果没有设置运行时配置，使用默认配置
 	if y.runtimeConfig == nil {
-		y.runtimeConfig = DefaultDetectionOptions()
+		y.runtimeConfig = y.resolveOptions(nil)
 	}
 
 	// 获取原始图像尺寸
@@ -1472,62 +2195,12 @@ func (y *YOLO) detectWithPreprocessedData(inputData []float32, img image.Image)
 
 	// 直接使用传入的预处理数据，跳过预处理步骤
 
-	// 创建输入张量
-	var inputShape ort.Shape
-	if y.config.InputWidth > 0 && y.config.InputHeight > 0 {
-		// 使用自定义的宽度和高度
-		inputShape = ort.NewShape(1, 3, int64(y.config.InputHeight), int64(y.config.InputWidth))
-	} else {
-		// 使用正方形尺寸
-		inputShape = ort.NewShape(1, 3, int64(y.config.InputSize), int64(y.config.InputSize))
-	}
-	inputTensor, err := ort.NewTensor(inputShape, inputData)
-	if err != nil {
-		return nil, fmt.Errorf("无法创建输入张量: %v", err)
-	}
-	defer inputTensor.Destroy()
-
-	// 创建输出张量（智能适配模型输出形状）
-	var outputShape ort.Shape
-	var outputDataSize int
-
-	// 如果是第一次推理或者modelOutputShape包含动态维度，使用标准形状进行探测
-	if len(y.modelOutputShape) == 0 || containsDynamicDimension(y.modelOutputShape) {
-		// 使用标准YOLO输出形状进行第一次推理
-		outputShape = ort.NewShape(1, 84, 8400)
-		outputDataSize = 1 * 84 * 8400
-	} else {
-		// 使用已知的模型输出形状
-		outputShape = ort.NewShape(y.modelOutputShape...)
-		outputDataSize = 1
-		for _, dim := range y.modelOutputShape {
-			outputDataSize *= int(dim)
-		}
-	}
-
-	outputData := make([]float32, outputDataSize)
-	outputTensor, err := ort.NewTensor(outputShape, outputData)
+	// 推理并解析检测结果（模型输入尺寸坐标）
+	detections, err := y.inferDetections(inputData, false, timings)
 	if err != nil {
-		return nil, fmt.Errorf("无法创建输出张量: %v", err)
-	}
-	defer outputTensor.Destroy()
-
-	// 运行推理
-	err = y.session.Run([]ort.Value{inputTensor}, []ort.Value{outputTensor})
-
-	if err != nil {
-		return nil, fmt.Errorf("推理失败: %v", err)
-	}
-
-	// 获取实际的输出形状并更新模型信息
-	actualOutputShape := outputTensor.GetShape()
-	if len(y.modelOutputShape) == 0 || containsDynamicDimension(y.modelOutputShape) {
-		y.modelOutputShape = actualOutputShape
+		return nil, err
 	}
 
-	// 解析检测结果
-	detections := y.parseDetections(outputTensor.GetData(), actualOutputShape)
-
 	// 将坐标从模型输入尺寸转换回原始图像尺寸
 	var scaleX, scaleY float32
 	if y.config.InputWidth > 0 && y.config.InputHeight > 0 {
@@ -1547,12 +2220,8 @@ func (y *YOLO) detectWithPreprocessedData(inputData []float32, img image.Image)
 		detections[i].Box[3] *= scaleY // y2
 	}
 
-	// 应用非极大抑制
-	threshold := float32(0.5) // 默认值
-	if y.runtimeConfig != nil {
-		threshold = y.runtimeConfig.IOUThreshold
-	}
-	keep := y.nonMaxSuppression(detections, threshold)
+	// 应用非极大抑制（或加权框融合）
+	keep := y.suppressOverlaps(detections)
 
 	return keep, nil
 }
@@ -1563,11 +2232,11 @@ func (y *YOLO) detectWithPreprocessedData(inputData []float32, img image.Image)
 func (y *YOLO) ShowLive(inputPath string) error {
 	// 如果没有设置运行时配置，使用默认配置
 	if y.runtimeConfig == nil {
-		y.runtimeConfig = DefaultDetectionOptions()
+		y.runtimeConfig = y.resolveOptions(nil)
 	}
 
 	if !isVideoFile(inputPath) {
-		return fmt.Errorf("不支持的文件格式，请使用MP4等视频文件")
+		return fmt.Errorf("%w，请使用MP4等视频文件", ErrUnsupportedFormat)
 	}
 
 	fmt.Printf("🎬 实时播放视频: %s\n", inputPath)
@@ -1607,7 +2276,7 @@ func (y *YOLO) ShowLive(inputPath string) error {
 
 		// 每10帧显示一次进度
 		if frameCount%10 == 0 {
-			fmt.Printf("📊 已处理 %d 帧...\n", frameCount)
+			y.verbosef("📊 已处理 %d 帧...\n", frameCount)
 		}
 	})
 
@@ -1686,20 +2355,16 @@ func StartLiveGUI(detector *YOLO, videoPath string, options *DetectionOptions) e
 // Detect 检测并返回结果（不保存），支持可选的回调函数
 func (y *YOLO) Detect(inputPath string, options *DetectionOptions, callbacks ...interface{}) (*DetectionResults, error) {
 	// 使用默认选项或传入的选项
-	opts := DefaultDetectionOptions()
-	if options != nil {
-		opts = options
-	}
+	opts := y.resolveOptions(options)
 
 	// 设置运行时配置
 	y.runtimeConfig = opts
 
 	// 处理图片文件
-	if strings.HasSuffix(strings.ToLower(inputPath), ".jpg") ||
-		strings.HasSuffix(strings.ToLower(inputPath), ".jpeg") ||
-		strings.HasSuffix(strings.ToLower(inputPath), ".png") {
+	if isImageFile(inputPath) {
 		// 图片：直接检测
 		detections, err := y.DetectImage(inputPath)
+		timings := y.LastTimings()
 
 		// 如果提供了回调函数，调用它
 		if len(callbacks) > 0 {
@@ -1732,6 +2397,7 @@ func (y *YOLO) Detect(inputPath string, options *DetectionOptions, callbacks ...
 			Detections: detections,
 			InputPath:  inputPath,
 			detector:   y,
+			Timings:    timings,
 		}
 
 		return y.lastDetections, nil
@@ -1761,10 +2427,21 @@ func (y *YOLO) Detect(inputPath string, options *DetectionOptions, callbacks ...
 			}
 
 			// 实时更新状态
-			fmt.Printf("📊 处理帧 %d, 检测到 %d 个对象\n", len(videoResults), len(result.Detections))
+			y.verbosef("📊 处理帧 %d, 检测到 %d 个对象\n", len(videoResults), len(result.Detections))
 		})
 
 		if err != nil {
+			if opts.PartialResults && len(videoResults) > 0 {
+				y.lastInputPath = inputPath
+				y.lastDetections = &DetectionResults{
+					Detections:   allDetections,
+					InputPath:    inputPath,
+					detector:     y,
+					VideoResults: videoResults,
+				}
+				fmt.Printf("⚠️  视频检测在 %d 帧后中断，返回已完成帧的结果\n", len(videoResults))
+				return y.lastDetections, fmt.Errorf("%w: 视频检测在 %d 帧后中断: %w", ErrPartialResults, len(videoResults), err)
+			}
 			return nil, fmt.Errorf("视频检测失败: %v", err)
 		}
 
@@ -1781,7 +2458,7 @@ func (y *YOLO) Detect(inputPath string, options *DetectionOptions, callbacks ...
 		return y.lastDetections, nil
 	}
 
-	return nil, fmt.Errorf("不支持的文件格式")
+	return nil, ErrUnsupportedFormat
 }
 
 // DetectFromCamera 从摄像头检测对象，统一使用VideoDetectionResult回调
@@ -1789,7 +2466,7 @@ func (y *YOLO) DetectFromCamera(device string, options *DetectionOptions, callba
 	fmt.Printf("📹 从摄像头检测: %s\n", device)
 
 	// 设置运行时配置
-	y.runtimeConfig = options
+	y.runtimeConfig = y.resolveOptions(options)
 
 	// 使用CameraVideoProcessor处理摄像头流
 	processor := NewCameraVideoProcessor(y, device)
@@ -1803,7 +2480,7 @@ func (y *YOLO) DetectFromCamera(device string, options *DetectionOptions, callba
 		allDetections = append(allDetections, result.Detections...)
 
 		// 实时更新状态
-		fmt.Printf("📊 摄像头帧 %d, 检测到 %d 个对象\n", frameCount, len(result.Detections))
+		y.verbosef("📊 摄像头帧 %d, 检测到 %d 个对象\n", frameCount, len(result.Detections))
 
 		// 如果提供了回调函数，调用它
 		if len(callback) > 0 && callback[0] != nil {
@@ -1837,7 +2514,7 @@ func (y *YOLO) DetectFromRTSP(rtspURL string, options *DetectionOptions, callbac
 	}
 
 	// 设置运行时配置
-	y.runtimeConfig = options
+	y.runtimeConfig = y.resolveOptions(options)
 
 	// 使用直接FFmpeg方式处理RTSP流
 	var allDetections []Detection
@@ -1849,7 +2526,7 @@ func (y *YOLO) DetectFromRTSP(rtspURL string, options *DetectionOptions, callbac
 		allDetections = append(allDetections, result.Detections...)
 
 		// 实时更新状态
-		fmt.Printf("📊 RTSP帧 %d, 检测到 %d 个对象\n", frameCount, len(result.Detections))
+		y.verbosef("📊 RTSP帧 %d, 检测到 %d 个对象\n", frameCount, len(result.Detections))
 
 		// 如果提供了回调函数，调用它
 		if len(callback) > 0 && callback[0] != nil {
@@ -1883,7 +2560,7 @@ func (y *YOLO) DetectFromScreen(options *DetectionOptions, callback ...func(Vide
 	}
 
 	// 设置运行时配置
-	y.runtimeConfig = options
+	y.runtimeConfig = y.resolveOptions(options)
 
 	// 使用Vidio处理屏幕流
 	processor := NewVidioVideoProcessor(y)
@@ -1897,7 +2574,7 @@ func (y *YOLO) DetectFromScreen(options *DetectionOptions, callback ...func(Vide
 		allDetections = append(allDetections, result.Detections...)
 
 		// 实时更新状态
-		fmt.Printf("📊 屏幕帧 %d, 检测到 %d 个对象\n", frameCount, len(result.Detections))
+		y.verbosef("📊 屏幕帧 %d, 检测到 %d 个对象\n", frameCount, len(result.Detections))
 
 		// 如果提供了回调函数，调用它
 		if len(callback) > 0 && callback[0] != nil {
@@ -1931,7 +2608,7 @@ func (y *YOLO) DetectFromRTMP(rtmpURL string, options *DetectionOptions, callbac
 	}
 
 	// 设置运行时配置
-	y.runtimeConfig = options
+	y.runtimeConfig = y.resolveOptions(options)
 
 	// 使用直接FFmpeg方式处理RTMP流
 	var allDetections []Detection
@@ -1943,7 +2620,7 @@ func (y *YOLO) DetectFromRTMP(rtmpURL string, options *DetectionOptions, callbac
 		allDetections = append(allDetections, result.Detections...)
 
 		// 实时更新状态
-		fmt.Printf("📊 RTMP帧 %d, 检测到 %d 个对象\n", frameCount, len(result.Detections))
+		y.verbosef("📊 RTMP帧 %d, 检测到 %d 个对象\n", frameCount, len(result.Detections))
 
 		// 如果提供了回调函数，调用它
 		if len(callback) > 0 && callback[0] != nil {
@@ -1969,34 +2646,32 @@ func (y *YOLO) DetectFromRTMP(rtmpURL string, options *DetectionOptions, callbac
 // loadClassesFromYAML 从YAML文件加载类别列表
 // loadImageForCallback 加载图片用于回调
 func (y *YOLO) loadImageForCallback(imagePath string) (image.Image, error) {
-	file, err := os.Open(imagePath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	img, _, err := image.Decode(file)
-	return img, err
+	return y.openImage(imagePath)
 }
 
 // saveVideoWithCachedResults 使用缓存的检测结果快速保存视频
 func (dr *DetectionResults) saveVideoWithCachedResults(outputPath string) error {
 	// 使用FFmpeg进行高质量编码，与SaveWithAudio保持一致
-	return dr.saveVideoWithFFmpeg(outputPath)
+	return dr.saveVideoWithFFmpeg(outputPath, nil)
 }
 
-// saveVideoWithFFmpeg 使用FFmpeg保存视频（无音频版本）
-func (dr *DetectionResults) saveVideoWithFFmpeg(outputPath string) error {
+// saveVideoWithFFmpeg 使用FFmpeg保存视频（无音频版本），videoOpts为nil时使用默认H.264编码
+func (dr *DetectionResults) saveVideoWithFFmpeg(outputPath string, videoOpts *VideoSaveOptions) error {
+	codecArgs, err := videoOpts.ffmpegArgs(outputPath)
+	if err != nil {
+		return err
+	}
+
 	// 创建临时目录存储帧
 	tempDir := filepath.Join(os.TempDir(), fmt.Sprintf("yolo_frames_%d", time.Now().UnixNano()))
-	err := os.MkdirAll(tempDir, 0755)
+	err = os.MkdirAll(tempDir, 0755)
 	if err != nil {
 		return fmt.Errorf("无法创建临时目录: %v", err)
 	}
 	defer os.RemoveAll(tempDir) // 清理临时文件
 
 	// 打开输入视频获取信息
-	video, err := vidio.NewVideo(dr.InputPath)
+	video, err := dr.detector.openVideo(dr.InputPath)
 	if err != nil {
 		return fmt.Errorf("无法打开视频文件: %v", err)
 	}
@@ -2005,7 +2680,8 @@ func (dr *DetectionResults) saveVideoWithFFmpeg(outputPath string) error {
 	fps := video.FPS()
 	fmt.Printf("📹 保存视频: %s -> %s (使用FFmpeg高质量编码)\n", dr.InputPath, outputPath)
 	frameCount := 0
-	resultIndex := 0
+	resultsByFrame := dr.detectionsByFrame()
+	clock := dr.detector.newFrameClock(dr.InputPath, fps)
 
 	// 逐帧处理并保存为图片
 	for video.Read() {
@@ -2014,21 +2690,12 @@ func (dr *DetectionResults) saveVideoWithFFmpeg(outputPath string) error {
 		// 将帧缓冲区转换为Go图像
 		frameImg := convertFrameBufferToImage(video.FrameBuffer(), video.Width(), video.Height())
 
-		// 使用缓存的检测结果（如果有的话）
-		var detections []Detection
-		if resultIndex < len(dr.VideoResults) && dr.VideoResults[resultIndex].FrameNumber == frameCount {
-			detections = dr.VideoResults[resultIndex].Detections
-			resultIndex++
-		} else {
-			// 如果没有对应帧的检测结果，使用空检测
-			detections = []Detection{}
-		}
+		// 按帧号查找缓存的检测结果，没有对应帧的结果时使用空检测
+		detections := resultsByFrame[frameCount]
 
-		// 绘制检测结果
-		var resultImg image.Image = frameImg
-		if len(detections) > 0 {
-			resultImg = dr.detector.drawDetectionsOnImage(frameImg, detections)
-		}
+		// 绘制检测结果和帧叠加信息
+		timestamp := clock.At(frameCount)
+		resultImg := dr.detector.annotateFrame(frameImg, detections, timestamp)
 
 		// 保存帧为图片
 		framePath := filepath.Join(tempDir, fmt.Sprintf("frame_%04d.jpg", frameCount))
@@ -2045,7 +2712,7 @@ func (dr *DetectionResults) saveVideoWithFFmpeg(outputPath string) error {
 
 		// 进度提示
 		if frameCount%30 == 0 {
-			fmt.Printf("📊 已处理 %d 帧...\n", frameCount)
+			dr.detector.verbosef("📊 已处理 %d 帧...\n", frameCount)
 		}
 	}
 
@@ -2053,13 +2720,9 @@ func (dr *DetectionResults) saveVideoWithFFmpeg(outputPath string) error {
 	args := []string{
 		"-r", fmt.Sprintf("%.0f", fps),
 		"-i", filepath.Join(tempDir, "frame_%04d.jpg"),
-		"-c:v", "libx264", // 使用H.264编码器
-		"-crf", "18", // CRF 18 视觉无损质量
-		"-preset", "slow", // slow预设获得更好压缩
-		"-pix_fmt", "yuv420p", // 使用yuv420p标准格式
-		"-y", // 覆盖输出文件
-		outputPath,
 	}
+	args = append(args, codecArgs...)
+	args = append(args, "-y", outputPath) // 覆盖输出文件
 
 	cmd := exec.Command("ffmpeg", args...)
 	cmd.Stderr = os.Stderr
@@ -2200,7 +2863,7 @@ func (y *YOLO) processRTSPWithFFmpeg(rtspURL string, options *DetectionOptions,
 
 		frameNumber++
 		if frameNumber%50 == 0 {
-			fmt.Printf("📊 已处理 %d 帧\n", frameNumber)
+			y.verbosef("📊 已处理 %d 帧\n", frameNumber)
 		}
 	}
 
@@ -2330,7 +2993,7 @@ func (y *YOLO) processRTMPWithFFmpeg(rtmpURL string, options *DetectionOptions,
 
 		frameNumber++
 		if frameNumber%50 == 0 {
-			fmt.Printf("📊 已处理 %d 帧\n", frameNumber)
+			y.verbosef("📊 已处理 %d 帧\n", frameNumber)
 		}
 	}
 
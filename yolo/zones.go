@@ -0,0 +1,230 @@
+package yolo
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Zone 命名的多边形区域（原图坐标）
+type Zone struct {
+	Name    string
+	Polygon [][2]float32 // 顶点 (x, y)，按顺序连接并自动闭合
+}
+
+// ZoneCounts 区域内按类别统计的计数
+type ZoneCounts struct {
+	Occupancy map[string]int // 当前帧区域内的目标数
+	Entries   map[string]int // 累计进入次数（仅统计有TrackID的目标）
+	Exits     map[string]int // 累计离开次数（仅统计有TrackID的目标）
+}
+
+// ZoneCounter 多边形区域计数器
+// 以检测框中心点判断目标是否在区域内，根据TrackID在帧间的状态变化统计进入/离开
+type ZoneCounter struct {
+	mu     sync.Mutex
+	zones  []Zone
+	inside map[string]map[int]string // 区域 -> 区域内的TrackID -> 类别
+	counts map[string]*ZoneCounts
+}
+
+// NewZoneCounter 创建区域计数器
+func NewZoneCounter() *ZoneCounter {
+	return &ZoneCounter{
+		inside: make(map[string]map[int]string),
+		counts: make(map[string]*ZoneCounts),
+	}
+}
+
+// AddZone 添加命名区域（至少3个顶点）
+func (zc *ZoneCounter) AddZone(name string, polygon [][2]float32) *ZoneCounter {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+
+	if len(polygon) < 3 {
+		fmt.Printf("⚠️  区域 %s 顶点不足3个，已忽略\n", name)
+		return zc
+	}
+
+	zc.zones = append(zc.zones, Zone{Name: name, Polygon: polygon})
+	zc.inside[name] = make(map[int]string)
+	zc.counts[name] = newZoneCounts()
+	return zc
+}
+
+// Update 使用一帧检测结果更新各区域的占用数和进出计数
+func (zc *ZoneCounter) Update(result VideoDetectionResult) {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+
+	for _, zone := range zc.zones {
+		counts := zc.counts[zone.Name]
+		counts.Occupancy = make(map[string]int)
+
+		previous := zc.inside[zone.Name]
+		current := make(map[int]string)
+
+		for _, det := range result.Detections {
+			cx := (det.Box[0] + det.Box[2]) / 2
+			cy := (det.Box[1] + det.Box[3]) / 2
+			if !pointInPolygon(cx, cy, zone.Polygon) {
+				continue
+			}
+
+			counts.Occupancy[det.Class]++
+			if det.TrackID <= 0 {
+				continue
+			}
+			current[det.TrackID] = det.Class
+			if _, ok := previous[det.TrackID]; !ok {
+				counts.Entries[det.Class]++
+			}
+		}
+
+		for trackID, class := range previous {
+			if _, ok := current[trackID]; !ok {
+				counts.Exits[class]++
+			}
+		}
+		zc.inside[zone.Name] = current
+	}
+}
+
+// Counts 返回指定区域的计数快照
+func (zc *ZoneCounter) Counts(zone string) (ZoneCounts, bool) {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+
+	counts, ok := zc.counts[zone]
+	if !ok {
+		return ZoneCounts{}, false
+	}
+	return counts.clone(), true
+}
+
+// AllCounts 返回所有区域的计数快照
+func (zc *ZoneCounter) AllCounts() map[string]ZoneCounts {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+
+	all := make(map[string]ZoneCounts, len(zc.counts))
+	for name, counts := range zc.counts {
+		all[name] = counts.clone()
+	}
+	return all
+}
+
+// Reset 清空所有计数和区域内的目标状态（保留区域定义）
+func (zc *ZoneCounter) Reset() {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+
+	for _, zone := range zc.zones {
+		zc.inside[zone.Name] = make(map[int]string)
+		zc.counts[zone.Name] = newZoneCounts()
+	}
+}
+
+// Draw 在图像上绘制区域轮廓和实时计数
+func (zc *ZoneCounter) Draw(img *image.RGBA, lineColor color.Color, textColor color.Color) {
+	zc.mu.Lock()
+	defer zc.mu.Unlock()
+
+	for _, zone := range zc.zones {
+		for i := range zone.Polygon {
+			p1 := zone.Polygon[i]
+			p2 := zone.Polygon[(i+1)%len(zone.Polygon)]
+			drawLine(img, image.Pt(int(p1[0]), int(p1[1])), image.Pt(int(p2[0]), int(p2[1])), lineColor)
+		}
+
+		counts := zc.counts[zone.Name]
+		label := fmt.Sprintf("%s: %d in, +%d -%d", zone.Name,
+			sumCounts(counts.Occupancy), sumCounts(counts.Entries), sumCounts(counts.Exits))
+
+		// 标签绘制在区域第一个顶点附近
+		d := &font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(textColor),
+			Face: basicfont.Face7x13,
+			Dot:  fixed.P(int(zone.Polygon[0][0])+4, int(zone.Polygon[0][1])+15),
+		}
+		d.DrawString(label)
+	}
+}
+
+// String 返回计数摘要（按类别排序）
+func (c ZoneCounts) String() string {
+	classes := make(map[string]bool)
+	for _, m := range []map[string]int{c.Occupancy, c.Entries, c.Exits} {
+		for class := range m {
+			classes[class] = true
+		}
+	}
+	names := make([]string, 0, len(classes))
+	for class := range classes {
+		names = append(names, class)
+	}
+	sort.Strings(names)
+
+	summary := ""
+	for i, class := range names {
+		if i > 0 {
+			summary += ", "
+		}
+		summary += fmt.Sprintf("%s: %d in, +%d -%d", class, c.Occupancy[class], c.Entries[class], c.Exits[class])
+	}
+	return summary
+}
+
+// newZoneCounts 创建空计数
+func newZoneCounts() *ZoneCounts {
+	return &ZoneCounts{
+		Occupancy: make(map[string]int),
+		Entries:   make(map[string]int),
+		Exits:     make(map[string]int),
+	}
+}
+
+// clone 复制计数，避免调用方修改内部状态
+func (c *ZoneCounts) clone() ZoneCounts {
+	copyMap := func(m map[string]int) map[string]int {
+		result := make(map[string]int, len(m))
+		for k, v := range m {
+			result[k] = v
+		}
+		return result
+	}
+	return ZoneCounts{
+		Occupancy: copyMap(c.Occupancy),
+		Entries:   copyMap(c.Entries),
+		Exits:     copyMap(c.Exits),
+	}
+}
+
+// sumCounts 汇总所有类别的计数
+func sumCounts(m map[string]int) int {
+	total := 0
+	for _, v := range m {
+		total += v
+	}
+	return total
+}
+
+// pointInPolygon 射线法判断点是否在多边形内
+func pointInPolygon(x, y float32, polygon [][2]float32) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		xi, yi := polygon[i][0], polygon[i][1]
+		xj, yj := polygon[j][0], polygon[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}